@@ -1,27 +1,37 @@
 package manifest
 
 import (
+	"fmt"
+
 	yaml "gopkg.in/yaml.v2"
 )
 
 // ZiplineeBot allows to respond to any event coming from one of the integrations
 type ZiplineeBot struct {
-	Name            string             `yaml:"-"`
-	Builder         *ZiplineeBuilder   `yaml:"builder,omitempty"`
-	CloneRepository *bool              `yaml:"clone,omitempty" json:",omitempty"`
-	Triggers        []*ZiplineeTrigger `yaml:"triggers,omitempty" json:",omitempty"`
-	Stages          []*ZiplineeStage   `yaml:"-" json:",omitempty"`
+	Name            string                 `yaml:"-"`
+	Builder         *ZiplineeBuilder       `yaml:"builder,omitempty"`
+	CloneRepository *bool                  `yaml:"clone,omitempty" json:",omitempty"`
+	Triggers        []*ZiplineeTrigger     `yaml:"triggers,omitempty" json:",omitempty"`
+	Inputs          []*ZiplineeManualInput `yaml:"inputs,omitempty" json:",omitempty"`
+	Concurrency     *ZiplineeConcurrency   `yaml:"concurrency,omitempty" json:",omitempty"`
+	Stages          []*ZiplineeStage       `yaml:"-" json:",omitempty"`
+	Template        string                 `yaml:"template,omitempty" json:",omitempty"`
+	TemplateParams  map[string]string      `yaml:"templateParams,omitempty" json:",omitempty"`
 }
 
 // UnmarshalYAML customizes unmarshalling an ZiplineeBot
 func (bot *ZiplineeBot) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
 
 	var aux struct {
-		Name            string             `yaml:"-"`
-		Builder         *ZiplineeBuilder   `yaml:"builder"`
-		CloneRepository *bool              `yaml:"clone"`
-		Triggers        []*ZiplineeTrigger `yaml:"triggers"`
-		Stages          yaml.MapSlice      `yaml:"stages"`
+		Name            string                 `yaml:"-"`
+		Builder         *ZiplineeBuilder       `yaml:"builder"`
+		CloneRepository *bool                  `yaml:"clone"`
+		Triggers        []*ZiplineeTrigger     `yaml:"triggers"`
+		Inputs          []*ZiplineeManualInput `yaml:"inputs"`
+		Concurrency     *ZiplineeConcurrency   `yaml:"concurrency"`
+		Stages          yaml.MapSlice          `yaml:"stages"`
+		Template        string                 `yaml:"template"`
+		TemplateParams  map[string]string      `yaml:"templateParams"`
 	}
 
 	// unmarshal to auxiliary type
@@ -34,6 +44,10 @@ func (bot *ZiplineeBot) UnmarshalYAML(unmarshal func(interface{}) error) (err er
 	bot.Builder = aux.Builder
 	bot.CloneRepository = aux.CloneRepository
 	bot.Triggers = aux.Triggers
+	bot.Inputs = aux.Inputs
+	bot.Concurrency = aux.Concurrency
+	bot.Template = aux.Template
+	bot.TemplateParams = aux.TemplateParams
 
 	for _, mi := range aux.Stages {
 
@@ -44,7 +58,7 @@ func (bot *ZiplineeBot) UnmarshalYAML(unmarshal func(interface{}) error) (err er
 
 		var stage *ZiplineeStage
 		if err := yaml.Unmarshal(bytes, &stage); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("stages.%v", mi.Key), err)
 		}
 		if stage == nil {
 			stage = &ZiplineeStage{}
@@ -61,17 +75,25 @@ func (bot *ZiplineeBot) UnmarshalYAML(unmarshal func(interface{}) error) (err er
 func (bot *ZiplineeBot) MarshalYAML() (out interface{}, err error) {
 
 	var aux struct {
-		Name            string             `yaml:"-"`
-		Builder         *ZiplineeBuilder   `yaml:"builder,omitempty"`
-		CloneRepository *bool              `yaml:"clone,omitempty"`
-		Triggers        []*ZiplineeTrigger `yaml:"triggers,omitempty"`
-		Stages          yaml.MapSlice      `yaml:"stages,omitempty"`
+		Name            string                 `yaml:"-"`
+		Builder         *ZiplineeBuilder       `yaml:"builder,omitempty"`
+		CloneRepository *bool                  `yaml:"clone,omitempty"`
+		Triggers        []*ZiplineeTrigger     `yaml:"triggers,omitempty"`
+		Inputs          []*ZiplineeManualInput `yaml:"inputs,omitempty"`
+		Concurrency     *ZiplineeConcurrency   `yaml:"concurrency,omitempty"`
+		Stages          yaml.MapSlice          `yaml:"stages,omitempty"`
+		Template        string                 `yaml:"template,omitempty"`
+		TemplateParams  map[string]string      `yaml:"templateParams,omitempty"`
 	}
 
 	// map auxiliary properties
 	aux.Builder = bot.Builder
 	aux.CloneRepository = bot.CloneRepository
 	aux.Triggers = bot.Triggers
+	aux.Inputs = bot.Inputs
+	aux.Concurrency = bot.Concurrency
+	aux.Template = bot.Template
+	aux.TemplateParams = bot.TemplateParams
 
 	for _, stage := range bot.Stages {
 		aux.Stages = append(aux.Stages, yaml.MapItem{
@@ -82,3 +104,42 @@ func (bot *ZiplineeBot) MarshalYAML() (out interface{}, err error) {
 
 	return aux, err
 }
+
+// InitFromTemplate uses template values for values the bot doesn't set itself; release actions of the template don't apply to bots
+func (bot *ZiplineeBot) InitFromTemplate(releaseTemplates map[string]*ZiplineeReleaseTemplate) {
+
+	if bot.Template == "" {
+		return
+	}
+
+	// resolving returns a deep copy, so there's no pointers shared with other bots
+	template := resolveReleaseTemplate(bot.Template, releaseTemplates)
+	if template == nil {
+		return
+	}
+
+	if bot.Builder == nil {
+		bot.Builder = template.Builder
+	}
+	if bot.CloneRepository == nil {
+		bot.CloneRepository = template.CloneRepository
+	}
+	if len(bot.Triggers) == 0 {
+		bot.Triggers = template.Triggers
+	}
+	if len(bot.Inputs) == 0 {
+		bot.Inputs = template.Inputs
+	}
+	if bot.Concurrency == nil {
+		bot.Concurrency = template.Concurrency
+	}
+
+	// only the template's stages have placeholders for the template parameters
+	values := template.templateParameterValues(bot.TemplateParams)
+	for _, s := range template.Stages {
+		s.replaceTemplateParameters(values)
+	}
+
+	// stages of the bot override, remove or add to the template's stages by name
+	bot.Stages = mergeStages(template.Stages, bot.Stages)
+}
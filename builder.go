@@ -65,17 +65,17 @@ func (builder *ZiplineeBuilder) validate(preferences ZiplineeManifestPreferences
 			}
 		}
 
-		return fmt.Errorf("builder os should be one of: %v", strings.Join(allowedOperatingSystems, ", "))
+		return wrapManifestError("os", fmt.Errorf("builder os should be one of: %v", strings.Join(allowedOperatingSystems, ", ")))
 	}
 
 	tracks, ok := preferences.BuilderTracksPerOperatingSystem[builder.OperatingSystem]
 
 	if !ok {
-		return fmt.Errorf("no track preferences have been configured for os %v", builder.OperatingSystem)
+		return wrapManifestError("os", fmt.Errorf("no track preferences have been configured for os %v", builder.OperatingSystem))
 	}
 
 	if !foundation.StringArrayContains(tracks, builder.Track) {
-		return fmt.Errorf("builder track should be one of: %v", strings.Join(tracks, ", "))
+		return wrapManifestError("track", fmt.Errorf("builder track should be one of: %v", strings.Join(tracks, ", ")))
 	}
 
 	return nil
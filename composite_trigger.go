@@ -0,0 +1,354 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeOfDayRegex = regexp.MustCompile(`^([01]?[0-9]|2[0-3]):([0-5][0-9])$|^24:00$`)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ZiplineeCompositeTrigger combines sub-triggers for 'all' and 'any' triggers, optionally limited to time windows; for 'all' each
+// sub-trigger has to fire for an event in the stream, for 'any' one of them has to fire for the latest event
+type ZiplineeCompositeTrigger struct {
+	Triggers []*ZiplineeTrigger `yaml:"triggers,omitempty" json:"triggers,omitempty"`
+	// TimeWindows limit firing to events happening within one of the windows
+	TimeWindows []*ZiplineeTimeWindow `yaml:"timeWindows,omitempty" json:"timeWindows,omitempty"`
+	// Within limits how long before the latest event the events firing the other sub-triggers of an 'all' trigger can have happened, like 1h
+	Within string `yaml:"within,omitempty" json:"within,omitempty"`
+}
+
+// ZiplineeTimeWindow is a recurring window of time on certain days of the week, like mon-fri from 09:00 to 17:00
+type ZiplineeTimeWindow struct {
+	Days     []string `yaml:"days,omitempty" json:"days,omitempty"`
+	Start    string   `yaml:"start,omitempty" json:"start,omitempty"`
+	End      string   `yaml:"end,omitempty" json:"end,omitempty"`
+	Timezone string   `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// SetDefaults sets defaults for ZiplineeCompositeTrigger
+func (c *ZiplineeCompositeTrigger) SetDefaults(preferences ZiplineeManifestPreferences) {
+	for _, t := range c.Triggers {
+		if t != nil {
+			// sub-triggers don't have an action of their own, the composite trigger does
+			t.SetDefaults(preferences, TriggerTypeUnknown, "")
+		}
+	}
+	for _, w := range c.TimeWindows {
+		if w != nil {
+			w.SetDefaults()
+		}
+	}
+}
+
+// SetDefaults sets defaults for ZiplineeTimeWindow
+func (w *ZiplineeTimeWindow) SetDefaults() {
+	if w.Start == "" {
+		w.Start = "00:00"
+	}
+	if w.End == "" {
+		w.End = "24:00"
+	}
+}
+
+// Validate checks if ZiplineeCompositeTrigger is valid
+func (c *ZiplineeCompositeTrigger) Validate(compositeType string) (err error) {
+	if len(c.Triggers) == 0 {
+		return fmt.Errorf("Set %v.triggers in your trigger to one or more triggers", compositeType)
+	}
+
+	for i, t := range c.Triggers {
+		if t == nil {
+			return wrapManifestError(fmt.Sprintf("triggers[%v]", i), fmt.Errorf("Set a trigger in %v.triggers", compositeType))
+		}
+		if t.BuildAction != nil || t.ReleaseAction != nil || t.BotAction != nil || t.Concurrency != nil {
+			return wrapManifestError(fmt.Sprintf("triggers[%v]", i), fmt.Errorf("Do not set 'builds', 'releases', 'runs' or 'concurrency' on triggers in %v.triggers, set them on the %v trigger itself", compositeType, compositeType))
+		}
+		err = t.Validate(TriggerTypeUnknown, "")
+		if err != nil {
+			return wrapManifestError(fmt.Sprintf("triggers[%v]", i), err)
+		}
+	}
+
+	for i, w := range c.TimeWindows {
+		if w == nil {
+			continue
+		}
+		err = w.Validate()
+		if err != nil {
+			return wrapManifestError(fmt.Sprintf("timeWindows[%v]", i), err)
+		}
+	}
+
+	if c.Within != "" {
+		within, err := time.ParseDuration(c.Within)
+		if err != nil || within <= 0 {
+			return fmt.Errorf("Set %v.within in your trigger to a positive duration like 30m or 24h", compositeType)
+		}
+		if compositeType != "all" {
+			return fmt.Errorf("Only set within in your trigger for 'all' triggers")
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if ZiplineeTimeWindow is valid
+func (w *ZiplineeTimeWindow) Validate() (err error) {
+	for _, d := range w.Days {
+		if _, _, err := parseWeekdayRange(d); err != nil {
+			return err
+		}
+	}
+	if !timeOfDayRegex.MatchString(w.Start) {
+		return fmt.Errorf("Set start of the time window to a time of day like 09:00")
+	}
+	if !timeOfDayRegex.MatchString(w.End) {
+		return fmt.Errorf("Set end of the time window to a time of day like 17:00")
+	}
+	if w.Start == w.End {
+		return fmt.Errorf("Set start and end of the time window to a different time of day")
+	}
+	if _, err := loadCronLocation(w.Timezone); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Contains indicates whether the time falls within the window; windows ending before they start run past midnight, in which case the
+// days are the days the window starts on
+func (w *ZiplineeTimeWindow) Contains(t time.Time) bool {
+	location, err := loadCronLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+	t = t.In(location)
+
+	start := minuteOfDay(w.Start, 0)
+	end := minuteOfDay(w.End, 24*60)
+	minute := t.Hour()*60 + t.Minute()
+
+	day := t.Weekday()
+	switch {
+	case start < end:
+		if minute < start || minute >= end {
+			return false
+		}
+	case minute >= start:
+	case minute < end:
+		// the part after midnight belongs to the window that started the day before
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+
+	return w.containsDay(day)
+}
+
+func (w *ZiplineeTimeWindow) containsDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		from, to, err := parseWeekdayRange(d)
+		if err != nil {
+			continue
+		}
+		// ranges can wrap around the end of the week, like fri-mon
+		if from <= to && day >= from && day <= to {
+			return true
+		}
+		if from > to && (day >= from || day <= to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseWeekdayRange parses a day like mon or a range of days like mon-fri
+func parseWeekdayRange(value string) (from, to time.Weekday, err error) {
+	parts := strings.SplitN(strings.ToLower(strings.TrimSpace(value)), "-", 2)
+
+	var ok bool
+	if from, ok = weekdays[parts[0]]; !ok {
+		return from, to, fmt.Errorf("Invalid day %v in time window, use mon, tue, wed, thu, fri, sat or sun, or a range like mon-fri", value)
+	}
+	to = from
+	if len(parts) == 2 {
+		if to, ok = weekdays[parts[1]]; !ok {
+			return from, to, fmt.Errorf("Invalid day %v in time window, use mon, tue, wed, thu, fri, sat or sun, or a range like mon-fri", value)
+		}
+	}
+
+	return from, to, nil
+}
+
+// minuteOfDay returns the minutes since midnight for a time of day like 09:30, or the default value if it's not valid
+func minuteOfDay(value string, defaultValue int) int {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return defaultValue
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return defaultValue
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return defaultValue
+	}
+
+	return hours*60 + minutes
+}
+
+// FiresForEvents indicates whether the trigger fires for the last event in the stream, taking the events before it into account for
+// 'all' triggers; the events are ordered by the time they happened
+func (t *ZiplineeTrigger) FiresForEvents(events []*ZiplineeEvent) bool {
+	if len(events) == 0 || events[len(events)-1] == nil {
+		return false
+	}
+
+	return t.firesAt(events, len(events)-1)
+}
+
+// firesAt indicates whether the trigger fires for the event at the index, with the events before it as history
+func (t *ZiplineeTrigger) firesAt(events []*ZiplineeEvent, index int) bool {
+	switch {
+	case t.All != nil:
+		return t.All.firesAll(events, index)
+	case t.Any != nil:
+		return t.Any.firesAny(events, index)
+	}
+
+	return t.Fires(events[index])
+}
+
+func (c *ZiplineeCompositeTrigger) firesAny(events []*ZiplineeEvent, index int) bool {
+	if !c.inTimeWindows(events[index]) {
+		return false
+	}
+
+	for _, t := range c.Triggers {
+		if t != nil && t.firesAt(events, index) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ZiplineeCompositeTrigger) firesAll(events []*ZiplineeEvent, index int) bool {
+	if len(c.Triggers) == 0 || !c.inTimeWindows(events[index]) {
+		return false
+	}
+
+	// the latest event has to fire one of the sub-triggers, otherwise the trigger already fired or will fire for another event
+	firedByLatest := false
+	for _, t := range c.Triggers {
+		if t != nil && t.firesAt(events, index) {
+			firedByLatest = true
+			break
+		}
+	}
+	if !firedByLatest {
+		return false
+	}
+
+	latest, latestHasTime := eventTime(events[index])
+	within, err := time.ParseDuration(c.Within)
+	if err != nil {
+		within = 0
+	}
+
+	for _, t := range c.Triggers {
+		if t == nil {
+			return false
+		}
+		fired := false
+		for i := index; i >= 0 && !fired; i-- {
+			if events[i] == nil {
+				continue
+			}
+			if within > 0 {
+				// events without time can't be shown to be within the duration
+				at, hasTime := eventTime(events[i])
+				if !latestHasTime || !hasTime || latest.Sub(at) > within {
+					break
+				}
+			}
+			fired = t.firesAt(events, i)
+		}
+		if !fired {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *ZiplineeCompositeTrigger) inTimeWindows(e *ZiplineeEvent) bool {
+	if len(c.TimeWindows) == 0 {
+		return true
+	}
+
+	// events without time can't be shown to be in a window
+	t, hasTime := eventTime(e)
+	if !hasTime {
+		return false
+	}
+	for _, w := range c.TimeWindows {
+		if w != nil && w.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventTime returns when the event happened, falling back to the cron time for cron events; it returns false for events without
+// time, so matching doesn't depend on when it's evaluated
+func eventTime(e *ZiplineeEvent) (time.Time, bool) {
+	switch {
+	case !e.Time.IsZero():
+		return e.Time, true
+	case e.Cron != nil && !e.Cron.Time.IsZero():
+		return e.Cron.Time, true
+	}
+
+	return time.Time{}, false
+}
+
+// leafTriggers returns the triggers with an event source, which is the trigger itself or the sub-triggers of 'all' and 'any' triggers
+func (t *ZiplineeTrigger) leafTriggers() (leaves []*ZiplineeTrigger) {
+	var composite *ZiplineeCompositeTrigger
+	switch {
+	case t.All != nil:
+		composite = t.All
+	case t.Any != nil:
+		composite = t.Any
+	default:
+		return []*ZiplineeTrigger{t}
+	}
+
+	for _, sub := range composite.Triggers {
+		if sub != nil {
+			leaves = append(leaves, sub.leafTriggers()...)
+		}
+	}
+
+	return
+}
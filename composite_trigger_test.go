@@ -0,0 +1,381 @@
+package manifest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZiplineeCompositeTriggerValidate(t *testing.T) {
+	t.Run("ReturnsNoErrorForValidAllTrigger", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			All: &ZiplineeCompositeTrigger{
+				Triggers: []*ZiplineeTrigger{
+					{Release: &ZiplineeReleaseTrigger{Event: "finished", Status: "succeeded", Name: "self", Target: "staging"}},
+				},
+				TimeWindows: []*ZiplineeTimeWindow{
+					{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Amsterdam"},
+				},
+				Within: "2h",
+			},
+			ReleaseAction: &ZiplineeTriggerReleaseAction{Target: "production", Version: "same"},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeRelease, "production")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfNoSubTriggersAreSet", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Any:         &ZiplineeCompositeTrigger{},
+			BuildAction: &ZiplineeTriggerBuildAction{Branch: "master"},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeBuild, "")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorWithPathIfSubTriggerIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Any: &ZiplineeCompositeTrigger{
+				Triggers: []*ZiplineeTrigger{
+					{Pipeline: &ZiplineePipelineTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-api", Branch: "main"}},
+					{Pipeline: &ZiplineePipelineTrigger{Event: "unknown", Name: "github.com/ziplineeci/ziplinee-ci-web", Branch: "main"}},
+				},
+			},
+			BuildAction: &ZiplineeTriggerBuildAction{Branch: "master"},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeBuild, "")
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "any.triggers[1].pipeline", err.(*ManifestError).Path)
+		}
+	})
+
+	t.Run("ReturnsErrorIfSubTriggerHasAction", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Any: &ZiplineeCompositeTrigger{
+				Triggers: []*ZiplineeTrigger{
+					{
+						Pipeline:    &ZiplineePipelineTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-api", Branch: "main"},
+						BuildAction: &ZiplineeTriggerBuildAction{Branch: "master"},
+					},
+				},
+			},
+			BuildAction: &ZiplineeTriggerBuildAction{Branch: "master"},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeBuild, "")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfWithinIsSetForAnyTrigger", func(t *testing.T) {
+
+		composite := ZiplineeCompositeTrigger{
+			Triggers: []*ZiplineeTrigger{
+				{Manual: &ZiplineeManualTrigger{}},
+			},
+			Within: "1h",
+		}
+
+		// act
+		err := composite.Validate("any")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfWithinIsNotADuration", func(t *testing.T) {
+
+		composite := ZiplineeCompositeTrigger{
+			Triggers: []*ZiplineeTrigger{
+				{Manual: &ZiplineeManualTrigger{}},
+			},
+			Within: "a day",
+		}
+
+		// act
+		err := composite.Validate("all")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfTriggerHasCompositeAndOtherType", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Manual: &ZiplineeManualTrigger{},
+			Any: &ZiplineeCompositeTrigger{
+				Triggers: []*ZiplineeTrigger{
+					{Manual: &ZiplineeManualTrigger{}},
+				},
+			},
+			BuildAction: &ZiplineeTriggerBuildAction{Branch: "master"},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeBuild, "")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestZiplineeTimeWindowValidate(t *testing.T) {
+	t.Run("ReturnsErrorForUnknownDay", func(t *testing.T) {
+
+		window := ZiplineeTimeWindow{Days: []string{"mon-fry"}, Start: "09:00", End: "17:00"}
+
+		// act
+		err := window.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForInvalidTimeOfDay", func(t *testing.T) {
+
+		window := ZiplineeTimeWindow{Start: "9am", End: "17:00"}
+
+		// act
+		err := window.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForUnknownTimezone", func(t *testing.T) {
+
+		window := ZiplineeTimeWindow{Start: "09:00", End: "17:00", Timezone: "Europe/Atlantis"}
+
+		// act
+		err := window.Validate()
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestZiplineeTimeWindowContains(t *testing.T) {
+
+	amsterdam, _ := time.LoadLocation("Europe/Amsterdam")
+
+	testCases := []struct {
+		name     string
+		window   ZiplineeTimeWindow
+		time     time.Time
+		contains bool
+	}{
+		{"BusinessHours", ZiplineeTimeWindow{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Amsterdam"}, time.Date(2024, 3, 5, 9, 0, 0, 0, amsterdam), true},
+		{"BusinessHoursInUTC", ZiplineeTimeWindow{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Amsterdam"}, time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC), true},
+		{"AfterBusinessHours", ZiplineeTimeWindow{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Amsterdam"}, time.Date(2024, 3, 5, 17, 0, 0, 0, amsterdam), false},
+		{"Weekend", ZiplineeTimeWindow{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Amsterdam"}, time.Date(2024, 3, 9, 12, 0, 0, 0, amsterdam), false},
+		{"DayRangeAcrossWeekend", ZiplineeTimeWindow{Days: []string{"fri-mon"}, Start: "00:00", End: "24:00"}, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC), true},
+		{"SeparateDays", ZiplineeTimeWindow{Days: []string{"tue", "Thu"}, Start: "00:00", End: "24:00"}, time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC), true},
+		{"OvernightBeforeMidnight", ZiplineeTimeWindow{Days: []string{"fri"}, Start: "22:00", End: "02:00"}, time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC), true},
+		{"OvernightAfterMidnight", ZiplineeTimeWindow{Days: []string{"fri"}, Start: "22:00", End: "02:00"}, time.Date(2024, 3, 9, 1, 0, 0, 0, time.UTC), true},
+		{"OvernightAfterMidnightOfOtherDay", ZiplineeTimeWindow{Days: []string{"fri"}, Start: "22:00", End: "02:00"}, time.Date(2024, 3, 8, 1, 0, 0, 0, time.UTC), false},
+		{"OvernightOutside", ZiplineeTimeWindow{Start: "22:00", End: "02:00"}, time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.window.SetDefaults()
+
+			// act
+			contains := tc.window.Contains(tc.time)
+
+			assert.Equal(t, tc.contains, contains)
+		})
+	}
+}
+
+func TestZiplineeTriggerFiresForEvents(t *testing.T) {
+
+	stagingReleased := func(at time.Time) *ZiplineeEvent {
+		return &ZiplineeEvent{
+			Time:    at,
+			Release: &ZiplineeReleaseEvent{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Target: "staging", Event: "finished", Status: "succeeded"},
+		}
+	}
+	pipelineFinished := func(name string, at time.Time) *ZiplineeEvent {
+		return &ZiplineeEvent{
+			Time:     at,
+			Pipeline: &ZiplineePipelineEvent{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: name, Branch: "main", Event: "finished", Status: "succeeded"},
+		}
+	}
+	upstreamTriggers := []*ZiplineeTrigger{
+		{Pipeline: &ZiplineePipelineTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-contracts", Branch: "main"}},
+		{Pipeline: &ZiplineePipelineTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-foundation", Branch: "main"}},
+	}
+	businessHours := &ZiplineeTrigger{
+		All: &ZiplineeCompositeTrigger{
+			Triggers: []*ZiplineeTrigger{
+				{Release: &ZiplineeReleaseTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-api", Target: "staging"}},
+			},
+			TimeWindows: []*ZiplineeTimeWindow{
+				{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "UTC"},
+			},
+		},
+	}
+	tuesday := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ReturnsTrueForAllTriggerWithEventInTimeWindow", func(t *testing.T) {
+
+		// act
+		fires := businessHours.FiresForEvents([]*ZiplineeEvent{stagingReleased(tuesday.Add(10 * time.Hour))})
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerWithEventOutsideTimeWindow", func(t *testing.T) {
+
+		// act
+		fires := businessHours.Fires(stagingReleased(tuesday.Add(20 * time.Hour)))
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueForAnyTriggerIfEitherSubTriggerFires", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{Any: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}}
+
+		// act
+		fires := trigger.Fires(pipelineFinished("ziplinee-ci-foundation", tuesday))
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAnyTriggerIfNoSubTriggerFires", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{Any: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}}
+
+		// act
+		fires := trigger.Fires(pipelineFinished("ziplinee-ci-web", tuesday))
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerIfOnlyOneSubTriggerFired", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}}
+
+		// act
+		fires := trigger.Fires(pipelineFinished("ziplinee-ci-contracts", tuesday))
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueForAllTriggerIfEarlierEventsFiredOtherSubTriggers", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers, Within: "1h"}}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-contracts", tuesday),
+			pipelineFinished("ziplinee-ci-web", tuesday.Add(10*time.Minute)),
+			pipelineFinished("ziplinee-ci-foundation", tuesday.Add(30*time.Minute)),
+		})
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerIfEarlierEventIsNotWithinDuration", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers, Within: "1h"}}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-contracts", tuesday),
+			pipelineFinished("ziplinee-ci-foundation", tuesday.Add(90*time.Minute)),
+		})
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerIfLatestEventDoesNotFireAnySubTrigger", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-contracts", tuesday),
+			pipelineFinished("ziplinee-ci-foundation", tuesday.Add(10*time.Minute)),
+			pipelineFinished("ziplinee-ci-web", tuesday.Add(20*time.Minute)),
+		})
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerWithTimeWindowsIfEventHasNoTime", func(t *testing.T) {
+
+		event := stagingReleased(time.Time{})
+
+		// act
+		fires := businessHours.FiresForEvents([]*ZiplineeEvent{event})
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseForAllTriggerWithWithinIfEarlierEventHasNoTime", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers, Within: "1h"}}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-contracts", time.Time{}),
+			pipelineFinished("ziplinee-ci-foundation", tuesday),
+		})
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueForAllTriggerWithoutWithinIfEventsHaveNoTime", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{All: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-contracts", time.Time{}),
+			pipelineFinished("ziplinee-ci-foundation", time.Time{}),
+		})
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsTrueForNestedAnyInAllTrigger", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			All: &ZiplineeCompositeTrigger{
+				Triggers: []*ZiplineeTrigger{
+					{Any: &ZiplineeCompositeTrigger{Triggers: upstreamTriggers}},
+					{Release: &ZiplineeReleaseTrigger{Event: "finished", Status: "succeeded", Name: "github.com/ziplineeci/ziplinee-ci-api", Target: "staging"}},
+				},
+			},
+		}
+
+		// act
+		fires := trigger.FiresForEvents([]*ZiplineeEvent{
+			pipelineFinished("ziplinee-ci-foundation", tuesday),
+			stagingReleased(tuesday.Add(time.Hour)),
+		})
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseForEmptyStream", func(t *testing.T) {
+
+		// act
+		fires := businessHours.FiresForEvents([]*ZiplineeEvent{})
+
+		assert.False(t, fires)
+	})
+}
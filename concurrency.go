@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var concurrencyGroupRegex = regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+
+// ZiplineeConcurrency limits how many runs started by triggers execute at the same time; it can be set on a trigger, or on a release
+// or bot to apply to all of its triggers, in which case a trigger's own concurrency settings take precedence
+type ZiplineeConcurrency struct {
+	// Group shares the limits between all triggers with the same group, even across pipelines; defaults to the build, release or bot itself
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
+	// MaxInFlight is the number of runs in the group that can execute at the same time, others are queued
+	MaxInFlight int `yaml:"maxInFlight,omitempty" json:"maxInFlight,omitempty"`
+	// CancelInProgress cancels the running run in the group when a new one starts, instead of queueing the new one
+	CancelInProgress bool `yaml:"cancelInProgress,omitempty" json:"cancelInProgress,omitempty"`
+	// Debounce waits for the trigger to stop firing for this duration, like 30s or 5m, and then starts a single run for the last event
+	Debounce string `yaml:"debounce,omitempty" json:"debounce,omitempty"`
+}
+
+// ZiplineeResolvedConcurrency is the concurrency policy in the form to enforce it for a trigger match, with defaults applied
+type ZiplineeResolvedConcurrency struct {
+	Group            string
+	MaxInFlight      int
+	CancelInProgress bool
+	Debounce         time.Duration
+}
+
+// SetDefaults sets defaults for ZiplineeConcurrency
+func (c *ZiplineeConcurrency) SetDefaults() {
+	if c.MaxInFlight == 0 {
+		c.MaxInFlight = 1
+	}
+}
+
+// Validate checks if ZiplineeConcurrency is valid
+func (c *ZiplineeConcurrency) Validate() (err error) {
+	if c.Group != "" && !concurrencyGroupRegex.MatchString(c.Group) {
+		return fmt.Errorf("Invalid concurrency group %v, only use letters, digits, dots, dashes, underscores and slashes", c.Group)
+	}
+	if c.MaxInFlight < 1 {
+		return fmt.Errorf("Set concurrency maxInFlight to 1 or more")
+	}
+	if c.CancelInProgress && c.MaxInFlight > 1 {
+		return fmt.Errorf("Do not set concurrency maxInFlight to more than 1 with cancelInProgress, a new run cancels the running one")
+	}
+	if c.Debounce != "" {
+		debounce, err := time.ParseDuration(c.Debounce)
+		if err != nil {
+			return fmt.Errorf("Invalid concurrency debounce %v, set it to a duration like 30s or 5m", c.Debounce)
+		}
+		if debounce < 0 {
+			return fmt.Errorf("Set concurrency debounce %v to a duration of 0 or more", c.Debounce)
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the policy for a build, release or bot of a pipeline, with the group defaulting to a group for just that build,
+// release or bot, i.e. github.com/ziplineeci/ziplinee-ci-api/release/production
+func (c *ZiplineeConcurrency) Resolve(pipelineName string, triggerType TriggerType, targetName string) *ZiplineeResolvedConcurrency {
+
+	resolved := &ZiplineeResolvedConcurrency{
+		Group:            c.Group,
+		MaxInFlight:      c.MaxInFlight,
+		CancelInProgress: c.CancelInProgress,
+	}
+
+	if resolved.Group == "" {
+		resolved.Group = fmt.Sprintf("%v/%v", pipelineName, triggerType)
+		if targetName != "" {
+			resolved.Group += "/" + targetName
+		}
+	}
+	if resolved.MaxInFlight < 1 {
+		resolved.MaxInFlight = 1
+	}
+	if c.Debounce != "" {
+		if debounce, err := time.ParseDuration(c.Debounce); err == nil && debounce > 0 {
+			resolved.Debounce = debounce
+		}
+	}
+
+	return resolved
+}
+
+// resolveConcurrency returns the resolved policy of the trigger, falling back to the one of the release or bot it belongs to, or nil if neither has one
+func resolveConcurrency(t *ZiplineeTrigger, targetConcurrency *ZiplineeConcurrency, pipelineName string, triggerType TriggerType, targetName string) *ZiplineeResolvedConcurrency {
+	concurrency := targetConcurrency
+	if t != nil && t.Concurrency != nil {
+		concurrency = t.Concurrency
+	}
+	if concurrency == nil {
+		return nil
+	}
+
+	return concurrency.Resolve(pipelineName, triggerType, targetName)
+}
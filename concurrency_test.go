@@ -0,0 +1,198 @@
+package manifest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZiplineeConcurrencySetDefaults(t *testing.T) {
+	t.Run("DefaultsMaxInFlightTo1", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{}
+
+		// act
+		concurrency.SetDefaults()
+
+		assert.Equal(t, 1, concurrency.MaxInFlight)
+	})
+
+	t.Run("KeepsMaxInFlightIfSet", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight: 3,
+		}
+
+		// act
+		concurrency.SetDefaults()
+
+		assert.Equal(t, 3, concurrency.MaxInFlight)
+	})
+}
+
+func TestZiplineeConcurrencyValidate(t *testing.T) {
+	t.Run("ReturnsNoErrorForValidConcurrency", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			Group:            "production-db/migrations",
+			MaxInFlight:      1,
+			CancelInProgress: true,
+			Debounce:         "2m30s",
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfGroupHasInvalidCharacters", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			Group:       "production deploys",
+			MaxInFlight: 1,
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfMaxInFlightIsLessThan1", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight: -1,
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfCancelInProgressIsCombinedWithMaxInFlightAbove1", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight:      2,
+			CancelInProgress: true,
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfDebounceIsNotADuration", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight: 1,
+			Debounce:    "5 minutes",
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfDebounceIsNegative", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight: 1,
+			Debounce:    "-30s",
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfDebounceIsZero", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			MaxInFlight: 1,
+			Debounce:    "0s",
+		}
+
+		// act
+		err := concurrency.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeConcurrencyResolve(t *testing.T) {
+	t.Run("ReturnsPolicyWithParsedDebounce", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{
+			Group:            "production-deploys",
+			MaxInFlight:      1,
+			CancelInProgress: true,
+			Debounce:         "5m",
+		}
+
+		// act
+		resolved := concurrency.Resolve("github.com/ziplineeci/ziplinee-ci-api", TriggerTypeRelease, "production")
+
+		assert.Equal(t, &ZiplineeResolvedConcurrency{
+			Group:            "production-deploys",
+			MaxInFlight:      1,
+			CancelInProgress: true,
+			Debounce:         5 * time.Minute,
+		}, resolved)
+	})
+
+	t.Run("DefaultsGroupToReleaseTarget", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{}
+
+		// act
+		resolved := concurrency.Resolve("github.com/ziplineeci/ziplinee-ci-api", TriggerTypeRelease, "production")
+
+		assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api/release/production", resolved.Group)
+		assert.Equal(t, 1, resolved.MaxInFlight)
+		assert.Equal(t, time.Duration(0), resolved.Debounce)
+	})
+
+	t.Run("DefaultsGroupToPipelineBuild", func(t *testing.T) {
+
+		concurrency := ZiplineeConcurrency{}
+
+		// act
+		resolved := concurrency.Resolve("github.com/ziplineeci/ziplinee-ci-api", TriggerTypeBuild, "")
+
+		assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api/build", resolved.Group)
+	})
+
+	t.Run("ReturnsTriggerPolicyOverTargetPolicy", func(t *testing.T) {
+
+		trigger := &ZiplineeTrigger{
+			Concurrency: &ZiplineeConcurrency{
+				MaxInFlight: 3,
+			},
+		}
+		targetConcurrency := &ZiplineeConcurrency{
+			MaxInFlight:      1,
+			CancelInProgress: true,
+		}
+
+		// act
+		resolved := resolveConcurrency(trigger, targetConcurrency, "github.com/ziplineeci/ziplinee-ci-api", TriggerTypeBot, "any-bot")
+
+		assert.Equal(t, 3, resolved.MaxInFlight)
+		assert.False(t, resolved.CancelInProgress)
+		assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api/bot/any-bot", resolved.Group)
+	})
+
+	t.Run("ReturnsNilWithoutTriggerOrTargetPolicy", func(t *testing.T) {
+
+		// act
+		resolved := resolveConcurrency(&ZiplineeTrigger{}, nil, "github.com/ziplineeci/ziplinee-ci-api", TriggerTypeBuild, "")
+
+		assert.Nil(t, resolved)
+	})
+}
@@ -0,0 +1,182 @@
+package manifest
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+
+	// embed the timezone database so timezones in cron triggers can be validated in images without zoneinfo
+	_ "time/tzdata"
+)
+
+// cronStarBit is set by the cron parser on a field if it was a wildcard
+const cronStarBit = 1 << 63
+
+// cronMaxDays limits the search for the next fire time, like the cron package does
+const cronMaxDays = 5 * 366
+
+// cronHashedFieldBounds are the values H can resolve to per field; day of month stops at 28 so it exists in every month
+var cronHashedFieldBounds = [5][2]int{{0, 59}, {0, 23}, {1, 28}, {1, 12}, {0, 6}}
+
+// cronHashedTokenRegex matches H, H(0-29), H/15 and H(0-29)/10
+var cronHashedTokenRegex = regexp.MustCompile(`^H(?:\((\d+)-(\d+)\))?(?:/(\d+))?$`)
+
+// resolveHashedCronSchedule replaces the jenkins style H tokens in a schedule with values derived from a hash of the seed, so
+// pipelines using the same schedule get spread over time while each pipeline keeps getting the same times
+func resolveHashedCronSchedule(schedule, seed string) (string, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 || !strings.Contains(schedule, "H") {
+		// leave it to the cron parser to report invalid schedules
+		return schedule, nil
+	}
+
+	for i, field := range fields {
+		items := strings.Split(field, ",")
+		for j, item := range items {
+			if !strings.HasPrefix(item, "H") {
+				continue
+			}
+			resolved, err := resolveHashedCronItem(item, i, seed)
+			if err != nil {
+				return schedule, err
+			}
+			items[j] = resolved
+		}
+		fields[i] = strings.Join(items, ",")
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+func resolveHashedCronItem(item string, fieldIndex int, seed string) (string, error) {
+	match := cronHashedTokenRegex.FindStringSubmatch(item)
+	if match == nil {
+		return item, fmt.Errorf("Invalid hashed value %v, use H, H(min-max), H/step or H(min-max)/step", item)
+	}
+
+	min, max := cronHashedFieldBounds[fieldIndex][0], cronHashedFieldBounds[fieldIndex][1]
+	if match[1] != "" {
+		rangeMin, _ := strconv.Atoi(match[1])
+		rangeMax, _ := strconv.Atoi(match[2])
+		if rangeMin > rangeMax || rangeMin < min || rangeMax > max {
+			return item, fmt.Errorf("Invalid range in hashed value %v, it should be within %v-%v", item, min, max)
+		}
+		min, max = rangeMin, rangeMax
+	}
+
+	// hash per field, so H H * * * doesn't result in the same minute and hour
+	hash := fnv.New32a()
+	hash.Write([]byte(fmt.Sprintf("%v:%v", seed, fieldIndex)))
+	sum := int(hash.Sum32() & 0x7fffffff)
+
+	if match[3] == "" {
+		return strconv.Itoa(min + sum%(max-min+1)), nil
+	}
+
+	step, _ := strconv.Atoi(match[3])
+	if step < 1 || step > max-min+1 {
+		return item, fmt.Errorf("Invalid step in hashed value %v, it should be between 1 and %v", item, max-min+1)
+	}
+
+	return fmt.Sprintf("%v-%v/%v", min+sum%step, max, step), nil
+}
+
+// loadCronLocation returns the location for an IANA timezone name, defaulting to UTC
+func loadCronLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	if timezone == "Local" {
+		return nil, fmt.Errorf("Timezone Local depends on the server the schedule is evaluated on, use an IANA timezone name like Europe/Amsterdam instead")
+	}
+
+	return time.LoadLocation(timezone)
+}
+
+// cronNextTimes returns the first n times the schedule fires after from, evaluated in the wall clock time of location
+func cronNextTimes(schedule cron.Schedule, location *time.Location, from time.Time, n int) (times []time.Time) {
+	times = []time.Time{}
+
+	spec, isSpec := schedule.(*cron.SpecSchedule)
+	for len(times) < n {
+		var next time.Time
+		if isSpec {
+			next = cronSpecNext(spec, location, from)
+		} else {
+			// schedules like @every 1h run at a fixed interval and do not depend on the timezone
+			next = schedule.Next(from).In(location)
+		}
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+		from = next
+	}
+
+	return
+}
+
+// cronSpecNext returns the first time after from at which the wall clock in location matches the schedule; a time skipped
+// by a daylight saving time transition fires at the shifted time, a time repeated by a transition only fires the first time
+func cronSpecNext(spec *cron.SpecSchedule, location *time.Location, from time.Time) time.Time {
+
+	local := from.In(location)
+	// iterate over calendar days in utc, so the days themselves are not affected by daylight saving time
+	startDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < cronMaxDays; i++ {
+		day := startDay.AddDate(0, 0, i)
+		if 1<<uint(day.Month())&spec.Month == 0 || !cronDayMatches(spec, day) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if 1<<uint(hour)&spec.Hour == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if 1<<uint(minute)&spec.Minute == 0 {
+					continue
+				}
+				t := cronWallClockTime(day.Year(), day.Month(), day.Day(), hour, minute, location)
+				if t.After(from) {
+					return t
+				}
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// cronDayMatches mirrors cron's behaviour of matching either day of month or day of week if both are restricted
+func cronDayMatches(spec *cron.SpecSchedule, day time.Time) bool {
+	domMatch := 1<<uint(day.Day())&spec.Dom > 0
+	dowMatch := 1<<uint(day.Weekday())&spec.Dow > 0
+	if spec.Dom&cronStarBit > 0 || spec.Dow&cronStarBit > 0 {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// cronWallClockTime returns the first instant the wall clock in location shows the given time
+func cronWallClockTime(year int, month time.Month, day, hour, minute int, location *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, location)
+
+	// for wall clock times that occur twice time.Date picks the second one, so check whether it occurred earlier as well
+	_, offset := t.Zone()
+	_, earlierOffset := t.Add(-12 * time.Hour).Zone()
+	if earlierOffset > offset {
+		earlier := t.Add(-time.Duration(earlierOffset-offset) * time.Second)
+		if earlier.Hour() == hour && earlier.Minute() == minute && earlier.Day() == day {
+			return earlier
+		}
+	}
+
+	return t
+}
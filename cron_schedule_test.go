@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveHashedCronSchedule(t *testing.T) {
+	t.Run("ReturnsScheduleWithoutHashedTokensUnchanged", func(t *testing.T) {
+
+		// act
+		schedule, err := resolveHashedCronSchedule("*/5 2 * * MON-FRI", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "*/5 2 * * MON-FRI", schedule)
+	})
+
+	t.Run("ReturnsSameScheduleForSamePipeline", func(t *testing.T) {
+
+		// act
+		first, err := resolveHashedCronSchedule("H H * * *", "github.com/ziplineeci/ziplinee-ci-api")
+		second, _ := resolveHashedCronSchedule("H H * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Equal(t, first, second)
+		assert.NotContains(t, first, "H")
+	})
+
+	t.Run("SpreadsSchedulesOverPipelines", func(t *testing.T) {
+
+		schedules := map[string]bool{}
+
+		// act
+		for _, name := range []string{"ziplinee-ci-api", "ziplinee-ci-web", "ziplinee-ci-builder", "ziplinee-ci-manifest", "ziplinee-ci-contracts"} {
+			schedule, err := resolveHashedCronSchedule("H 2 * * *", "github.com/ziplineeci/"+name)
+			assert.Nil(t, err)
+			schedules[schedule] = true
+		}
+
+		assert.True(t, len(schedules) > 1)
+	})
+
+	t.Run("ResolvesHashedValueWithinRange", func(t *testing.T) {
+
+		// act
+		schedule, err := resolveHashedCronSchedule("H(0-9) H(1-3) * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Regexp(t, `^[0-9] [1-3] \* \* \*$`, schedule)
+	})
+
+	t.Run("ResolvesHashedStepToRangeWithOffsetStart", func(t *testing.T) {
+
+		// act
+		schedule, err := resolveHashedCronSchedule("H/15 * * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Regexp(t, `^([0-9]|1[0-4])-59/15 \* \* \* \*$`, schedule)
+	})
+
+	t.Run("ResolvesHashedValuesInLists", func(t *testing.T) {
+
+		// act
+		schedule, err := resolveHashedCronSchedule("0 H(0-5),12 * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Regexp(t, `^0 [0-5],12 \* \* \*$`, schedule)
+	})
+
+	t.Run("ReturnsErrorIfRangeIsOutOfBounds", func(t *testing.T) {
+
+		// act
+		_, err := resolveHashedCronSchedule("H(0-75) * * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfHashedTokenIsMalformed", func(t *testing.T) {
+
+		// act
+		_, err := resolveHashedCronSchedule("H5 * * * *", "github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.NotNil(t, err)
+	})
+}
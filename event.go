@@ -4,13 +4,14 @@ import "time"
 
 // ZiplineePipelineEvent fires for pipeline changes
 type ZiplineePipelineEvent struct {
-	BuildVersion string `yaml:"buildVersion,omitempty" json:"buildVersion,omitempty"`
-	RepoSource   string `yaml:"repoSource,omitempty" json:"repoSource,omitempty"`
-	RepoOwner    string `yaml:"repoOwner,omitempty" json:"repoOwner,omitempty"`
-	RepoName     string `yaml:"repoName,omitempty" json:"repoName,omitempty"`
-	Branch       string `yaml:"repoBranch,omitempty" json:"repoBranch,omitempty"`
-	Status       string `yaml:"status,omitempty" json:"status,omitempty"`
-	Event        string `yaml:"event,omitempty" json:"event,omitempty"`
+	BuildVersion string   `yaml:"buildVersion,omitempty" json:"buildVersion,omitempty"`
+	RepoSource   string   `yaml:"repoSource,omitempty" json:"repoSource,omitempty"`
+	RepoOwner    string   `yaml:"repoOwner,omitempty" json:"repoOwner,omitempty"`
+	RepoName     string   `yaml:"repoName,omitempty" json:"repoName,omitempty"`
+	Branch       string   `yaml:"repoBranch,omitempty" json:"repoBranch,omitempty"`
+	Status       string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Event        string   `yaml:"event,omitempty" json:"event,omitempty"`
+	ChangedFiles []string `yaml:"changedFiles,omitempty" json:"changedFiles,omitempty"`
 }
 
 // ZiplineeReleaseEvent fires for pipeline releases
@@ -26,9 +27,13 @@ type ZiplineeReleaseEvent struct {
 
 // ZiplineeGitEvent fires for git repository changes
 type ZiplineeGitEvent struct {
-	Event      string `yaml:"event,omitempty" json:"event,omitempty"`
-	Repository string `yaml:"repository,omitempty" json:"repository,omitempty"`
-	Branch     string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Event        string   `yaml:"event,omitempty" json:"event,omitempty"`
+	Repository   string   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Branch       string   `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Tag          string   `yaml:"tag,omitempty" json:"tag,omitempty"`
+	SourceBranch string   `yaml:"sourceBranch,omitempty" json:"sourceBranch,omitempty"`
+	TargetBranch string   `yaml:"targetBranch,omitempty" json:"targetBranch,omitempty"`
+	ChangedFiles []string `yaml:"changedFiles,omitempty" json:"changedFiles,omitempty"`
 }
 
 // ZiplineeDockerEvent fires for docker image changes
@@ -45,7 +50,8 @@ type ZiplineeCronEvent struct {
 
 // ZiplineeManualEvent fires when a user manually triggers a build or release
 type ZiplineeManualEvent struct {
-	UserID string `yaml:"userID,omitempty" json:"userID,omitempty"`
+	UserID     string            `yaml:"userID,omitempty" json:"userID,omitempty"`
+	Parameters map[string]string `yaml:"parameters,omitempty" json:"parameters,omitempty"`
 }
 
 // ZiplineePubSubEvent fires when a subscribed pubsub topic receives an event
@@ -73,10 +79,26 @@ type ZiplineeBitbucketEvent struct {
 	Payload       string `yaml:"payload,omitempty" json:"payload,omitempty"`
 }
 
+// ZiplineeGitlabEvent fires for gitlab events
+type ZiplineeGitlabEvent struct {
+	Event      string `yaml:"event,omitempty" json:"event,omitempty"`
+	Repository string `yaml:"repository,omitempty" json:"repository,omitempty"`
+	EventUUID  string `yaml:"eventUUID,omitempty" json:"eventUUID,omitempty"`
+	Payload    string `yaml:"payload,omitempty" json:"payload,omitempty"`
+}
+
+// ZiplineeWebhookEvent fires when a generic http webhook is posted to the endpoint with its name
+type ZiplineeWebhookEvent struct {
+	Name    string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Payload string            `yaml:"payload,omitempty" json:"payload,omitempty"`
+}
+
 // ZiplineeEvent is a container for any trigger event
 type ZiplineeEvent struct {
 	Name      string                  `yaml:"name,omitempty" json:"name,omitempty"`
 	Fired     bool                    `yaml:"fired,omitempty" json:"fired,omitempty"`
+	Time      time.Time               `yaml:"time,omitempty" json:"time,omitempty"`
 	Pipeline  *ZiplineePipelineEvent  `yaml:"pipeline,omitempty" json:"pipeline,omitempty"`
 	Release   *ZiplineeReleaseEvent   `yaml:"release,omitempty" json:"release,omitempty"`
 	Git       *ZiplineeGitEvent       `yaml:"git,omitempty" json:"git,omitempty"`
@@ -85,5 +107,7 @@ type ZiplineeEvent struct {
 	PubSub    *ZiplineePubSubEvent    `yaml:"pubsub,omitempty" json:"pubsub,omitempty"`
 	Github    *ZiplineeGithubEvent    `yaml:"github,omitempty" json:"github,omitempty"`
 	Bitbucket *ZiplineeBitbucketEvent `yaml:"bitbucket,omitempty" json:"bitbucket,omitempty"`
+	Gitlab    *ZiplineeGitlabEvent    `yaml:"gitlab,omitempty" json:"gitlab,omitempty"`
+	Webhook   *ZiplineeWebhookEvent   `yaml:"webhook,omitempty" json:"webhook,omitempty"`
 	Manual    *ZiplineeManualEvent    `yaml:"manual,omitempty" json:"manual,omitempty"`
 }
@@ -0,0 +1,352 @@
+package manifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// includeSections are the top level sections an included fragment can set; everything else can only be set in the manifest itself
+var includeSections = []string{"include", "stages", "stageTemplates", "releaseTemplates", "env", "labels"}
+
+// Resolver returns the content of includes; an include ending in .yaml or .yml is a path relative to the repository root, or
+// relative to the including fragment if that is a path itself, while any other include is a name, like one in a central store
+type Resolver interface {
+	Resolve(include string) ([]byte, error)
+}
+
+// FileResolver resolves includes by reading them from a directory, usually the root of the repository
+type FileResolver struct {
+	Dir string
+}
+
+// Resolve reads the include relative to the resolver's directory
+func (r *FileResolver) Resolve(include string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(r.Dir, filepath.FromSlash(include)))
+}
+
+// manifestFragment holds the sections of a manifest or included fragment that get merged
+type manifestFragment struct {
+	Includes            []string          `yaml:"include"`
+	Labels              map[string]string `yaml:"labels"`
+	GlobalEnvVars       map[string]string `yaml:"env"`
+	Stages              yaml.MapSlice     `yaml:"stages"`
+	DeprecatedPipelines yaml.MapSlice     `yaml:"pipelines"`
+	StageTemplates      yaml.MapSlice     `yaml:"stageTemplates"`
+	ReleaseTemplates    yaml.MapSlice     `yaml:"releaseTemplates"`
+}
+
+// manifestSource is the manifest itself or one of its includes
+type manifestSource struct {
+	name       string
+	data       []byte
+	isFragment bool
+	isPath     bool
+}
+
+// manifestLayer is a fragment to merge, with its position in the include tree; the manifest comes first, each fragment followed by its own includes
+type manifestLayer struct {
+	source   *manifestSource
+	fragment *manifestFragment
+	position int
+}
+
+// manifestSources keeps track of which source the merged stages, stage and release templates, env vars and labels come from, so errors can be
+// located in the file they're defined in
+type manifestSources struct {
+	main    *manifestSource
+	entries map[string]*manifestSource
+}
+
+func newManifestSources(file string, data []byte) *manifestSources {
+	return &manifestSources{
+		main:    &manifestSource{name: file, data: data},
+		entries: map[string]*manifestSource{},
+	}
+}
+
+// includeManifestFragments merges the includes of the manifest into it and unmarshals the result; the manifest's own definitions
+// override those of includes and later includes override earlier ones, while stages keep the order they're first defined in, with
+// the manifest's own stages first, followed by included stages in the order they're included
+func (s *manifestSources) includeManifestFragments(resolver Resolver) (manifest ZiplineeManifest, err error) {
+
+	var fragment manifestFragment
+	if err := yaml.Unmarshal(s.main.data, &fragment); err != nil {
+		return manifest, locateManifestError(s.main.data, s.main.name, err)
+	}
+	if len(fragment.Stages) == 0 {
+		fragment.Stages = fragment.DeprecatedPipelines
+	}
+
+	layers := []manifestLayer{}
+	position := 0
+	if err := s.expandIncludes(s.main, &fragment, resolver, []string{}, &layers, &position); err != nil {
+		return manifest, err
+	}
+
+	// merge the sections of all layers, the layers are ordered from lowest to highest precedence
+	stages := s.mergeMapSlices(layers, "stages", func(f *manifestFragment) yaml.MapSlice { return f.Stages })
+	stageTemplates := s.mergeMapSlices(layers, "stageTemplates", func(f *manifestFragment) yaml.MapSlice { return f.StageTemplates })
+	releaseTemplates := s.mergeMapSlices(layers, "releaseTemplates", func(f *manifestFragment) yaml.MapSlice { return f.ReleaseTemplates })
+	env := s.mergeMaps(layers, "env", func(f *manifestFragment) map[string]string { return f.GlobalEnvVars })
+	labels := s.mergeMaps(layers, "labels", func(f *manifestFragment) map[string]string { return f.Labels })
+
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(s.main.data, &document); err != nil {
+		return manifest, locateManifestError(s.main.data, s.main.name, err)
+	}
+	document = removeMapSliceItem(document, "pipelines")
+	document = setMapSliceItem(document, "stages", stages)
+	document = setMapSliceItem(document, "stageTemplates", stageTemplates)
+	document = setMapSliceItem(document, "releaseTemplates", releaseTemplates)
+	document = setMapSliceItem(document, "env", env)
+	document = setMapSliceItem(document, "labels", labels)
+
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return manifest, err
+	}
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return manifest, s.locateError(err)
+	}
+
+	return manifest, nil
+}
+
+// expandIncludes resolves the includes of a fragment depth first and adds the layers in order of precedence, lowest first
+func (s *manifestSources) expandIncludes(source *manifestSource, fragment *manifestFragment, resolver Resolver, stack []string, layers *[]manifestLayer, position *int) error {
+
+	layer := manifestLayer{source: source, fragment: fragment, position: *position}
+	*position++
+
+	stack = append(stack, source.name)
+
+	for i, include := range fragment.Includes {
+		includePath := fmt.Sprintf("include[%v]", i)
+
+		name, isPath, err := includeName(source, include)
+		if err != nil {
+			return locateManifestError(source.data, source.name, wrapManifestError(includePath, err))
+		}
+		for _, n := range stack {
+			if n == name {
+				return locateManifestError(source.data, source.name, wrapManifestError(includePath, fmt.Errorf("Include %v includes itself via %v", name, strings.Join(append(stack[1:], name), " > "))))
+			}
+		}
+		if resolver == nil {
+			return locateManifestError(source.data, source.name, wrapManifestError(includePath, fmt.Errorf("Include %v can't be resolved, read the manifest with a resolver to use includes", name)))
+		}
+
+		data, err := resolver.Resolve(name)
+		if err != nil {
+			return locateManifestError(source.data, source.name, wrapManifestError(includePath, fmt.Errorf("Include %v can't be resolved: %v", name, err)))
+		}
+
+		includeSource := &manifestSource{name: name, data: data, isFragment: true, isPath: isPath}
+		includeFragment, err := parseManifestFragment(includeSource)
+		if err != nil {
+			return err
+		}
+
+		if err := s.expandIncludes(includeSource, includeFragment, resolver, stack, layers, position); err != nil {
+			return err
+		}
+	}
+
+	*layers = append(*layers, layer)
+
+	return nil
+}
+
+// includeName returns the name to resolve an include with, which for paths is the path relative to the repository root
+func includeName(source *manifestSource, include string) (name string, isPath bool, err error) {
+	include = strings.TrimSpace(include)
+	if include == "" {
+		return "", false, fmt.Errorf("Set include to a path relative to the repository like ci/push.yaml or a name")
+	}
+	if !strings.HasSuffix(include, ".yaml") && !strings.HasSuffix(include, ".yml") {
+		return include, false, nil
+	}
+	if path.IsAbs(include) {
+		return "", true, fmt.Errorf("Include %v should be a path relative to the repository", include)
+	}
+
+	name = path.Clean(include)
+	if source.isFragment && source.isPath {
+		// paths in fragments included by path are relative to the fragment itself
+		name = path.Join(path.Dir(source.name), include)
+	}
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", true, fmt.Errorf("Include %v points outside of the repository", include)
+	}
+
+	return name, true, nil
+}
+
+// parseManifestFragment checks the fragment only has sections that can be included and that they're valid
+func parseManifestFragment(source *manifestSource) (*manifestFragment, error) {
+
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(source.data, &document); err != nil {
+		return nil, locateManifestError(source.data, source.name, err)
+	}
+	for _, item := range document {
+		key := fmt.Sprintf("%v", item.Key)
+		allowed := false
+		for _, section := range includeSections {
+			if key == section {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return nil, locateManifestError(source.data, source.name, wrapManifestError(key, fmt.Errorf("Section %v can't be set in included fragments, only %v", key, strings.Join(includeSections, ", "))))
+		}
+	}
+
+	// unmarshal strict as manifest first, so errors in stages and release templates are reported for the fragment
+	var manifest ZiplineeManifest
+	if err := yaml.UnmarshalStrict(source.data, &manifest); err != nil {
+		return nil, locateManifestError(source.data, source.name, err)
+	}
+
+	var fragment manifestFragment
+	if err := yaml.UnmarshalStrict(source.data, &fragment); err != nil {
+		return nil, locateManifestError(source.data, source.name, err)
+	}
+
+	return &fragment, nil
+}
+
+// mergeMapSlices merges a section keyed by name, with each entry at the position it's first defined and the value with highest precedence
+func (s *manifestSources) mergeMapSlices(layers []manifestLayer, section string, get func(*manifestFragment) yaml.MapSlice) (merged yaml.MapSlice) {
+
+	values := map[string]interface{}{}
+	for _, l := range layers {
+		for _, item := range get(l.fragment) {
+			key := fmt.Sprintf("%v", item.Key)
+			values[key] = item.Value
+			s.entries[section+"."+key] = l.source
+		}
+	}
+
+	added := map[string]bool{}
+	for _, l := range sortLayersByPosition(layers) {
+		for _, item := range get(l.fragment) {
+			key := fmt.Sprintf("%v", item.Key)
+			if !added[key] {
+				added[key] = true
+				merged = append(merged, yaml.MapItem{Key: item.Key, Value: values[key]})
+			}
+		}
+	}
+
+	return
+}
+
+// mergeMaps merges a map section like env or labels, with values of higher precedence overriding lower ones
+func (s *manifestSources) mergeMaps(layers []manifestLayer, section string, get func(*manifestFragment) map[string]string) (merged map[string]string) {
+	for _, l := range layers {
+		for key, value := range get(l.fragment) {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			merged[key] = value
+			s.entries[section+"."+key] = l.source
+		}
+	}
+
+	return
+}
+
+func sortLayersByPosition(layers []manifestLayer) []manifestLayer {
+	sorted := make([]manifestLayer, len(layers))
+	for _, l := range layers {
+		sorted[l.position] = l
+	}
+
+	return sorted
+}
+
+func setMapSliceItem(document yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if len(v) == 0 {
+			return document
+		}
+	case map[string]string:
+		if len(v) == 0 {
+			return document
+		}
+	}
+
+	for i, item := range document {
+		if item.Key == key {
+			document[i].Value = value
+			return document
+		}
+	}
+
+	return append(document, yaml.MapItem{Key: key, Value: value})
+}
+
+func removeMapSliceItem(document yaml.MapSlice, key string) (result yaml.MapSlice) {
+	for _, item := range document {
+		if item.Key != key {
+			result = append(result, item)
+		}
+	}
+
+	return
+}
+
+// sourceFor returns the source an error path like stages.push.image originates from
+func (s *manifestSources) sourceFor(errorPath string) *manifestSource {
+	source := s.main
+	longest := 0
+	for entry, entrySource := range s.entries {
+		if len(entry) > longest && (errorPath == entry || strings.HasPrefix(errorPath, entry+".") || strings.HasPrefix(errorPath, entry+"[")) {
+			source = entrySource
+			longest = len(entry)
+		}
+	}
+
+	return source
+}
+
+// locateError locates the error, or each problem for a validation result, in the source it originates from
+func (s *manifestSources) locateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	result, ok := err.(*ValidationResult)
+	if !ok {
+		path := ""
+		if manifestError, ok := err.(*ManifestError); ok {
+			path = manifestError.Path
+		}
+		source := s.sourceFor(path)
+		return locateManifestError(source.data, source.name, err)
+	}
+
+	documents := map[*manifestSource]*yamlv3.Node{}
+	for _, p := range result.Problems {
+		source := s.sourceFor(p.Path)
+		document, ok := documents[source]
+		if !ok {
+			document = &yamlv3.Node{}
+			if yamlv3.Unmarshal(source.data, document) != nil {
+				document = &yamlv3.Node{}
+			}
+			documents[source] = document
+		}
+		locate(document, source.name, p.ManifestError)
+	}
+
+	return result
+}
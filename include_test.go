@@ -0,0 +1,341 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type mapResolver map[string]string
+
+func (r mapResolver) Resolve(include string) ([]byte, error) {
+	if data, ok := r[include]; ok {
+		return []byte(data), nil
+	}
+
+	return nil, fmt.Errorf("Include %v does not exist", include)
+}
+
+func TestReadManifestWithResolver(t *testing.T) {
+
+	resolver := mapResolver{
+		"ci/push.yaml": `
+env:
+  REGISTRY: ziplineeci
+  VERBOSE: "false"
+labels:
+  team: ziplinee-team
+stages:
+  push:
+    image: extensions/docker:stable
+    action: push
+  notify:
+    image: extensions/slack-build-status:stable`,
+		"ziplinee/release-templates": `
+releaseTemplates:
+  kubernetes:
+    stages:
+      deploy:
+        image: extensions/gke:stable`,
+		"ci/verbose.yaml": `
+env:
+  VERBOSE: "true"
+stages:
+  notify:
+    image: extensions/slack-build-status:dev`,
+	}
+
+	t.Run("MergesIncludedStagesAfterOwnStages", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/push.yaml
+stages:
+  build:
+    image: golang`, resolver, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 3, len(manifest.Stages)) {
+			assert.Equal(t, "build", manifest.Stages[0].Name)
+			assert.Equal(t, "push", manifest.Stages[1].Name)
+			assert.Equal(t, "notify", manifest.Stages[2].Name)
+		}
+		assert.Equal(t, "ziplineeci", manifest.GlobalEnvVars["REGISTRY"])
+		assert.Equal(t, "ziplinee-team", manifest.Labels["team"])
+		assert.Equal(t, []string{"ci/push.yaml"}, manifest.Includes)
+	})
+
+	t.Run("OverridesIncludesWithManifestAndEarlierIncludesWithLaterOnes", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/push.yaml
+- ci/verbose.yaml
+labels:
+  team: api-team
+stages:
+  build:
+    image: golang
+  push:
+    image: extensions/docker:dev
+    action: push`, resolver, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 3, len(manifest.Stages)) {
+			assert.Equal(t, "extensions/docker:dev", manifest.Stages[1].ContainerImage)
+			assert.Equal(t, "notify", manifest.Stages[2].Name)
+			assert.Equal(t, "extensions/slack-build-status:dev", manifest.Stages[2].ContainerImage)
+		}
+		assert.Equal(t, "true", manifest.GlobalEnvVars["VERBOSE"])
+		assert.Equal(t, "api-team", manifest.Labels["team"])
+	})
+
+	t.Run("UsesIncludedStageTemplatesForStages", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/stage-templates.yaml
+stages:
+  build:
+    template: go-build`, mapResolver{"ci/stage-templates.yaml": `
+stageTemplates:
+  go-build:
+    image: golang:1.22`}, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Stages)) {
+			assert.Equal(t, "golang:1.22", manifest.Stages[0].ContainerImage)
+		}
+	})
+
+	t.Run("UsesIncludedReleaseTemplatesForReleases", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ziplinee/release-templates
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    template: kubernetes`, resolver, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.Equal(t, 1, len(manifest.Releases[0].Stages)) {
+			assert.Equal(t, "extensions/gke:stable", manifest.Releases[0].Stages[0].ContainerImage)
+		}
+	})
+
+	t.Run("ReturnsErrorWithIncludeFileForInvalidIncludedStage", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/broken.yaml
+stages:
+  build:
+    image: golang`, mapResolver{"ci/broken.yaml": `
+stages:
+  push:
+    action: push`}, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "ci/broken.yaml", result.Errors()[0].File)
+			assert.Equal(t, "stages.push", result.Errors()[0].Path)
+			assert.Equal(t, 3, result.Errors()[0].Line)
+		}
+	})
+
+	t.Run("ReturnsErrorWithIncludeFileForSectionThatCannotBeIncluded", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/triggers.yaml
+stages:
+  build:
+    image: golang`, mapResolver{"ci/triggers.yaml": `
+stages:
+  push:
+    image: extensions/docker:stable
+triggers:
+- cron:
+    schedule: '0 * * * *'`}, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "ci/triggers.yaml", manifestError.File)
+			assert.Equal(t, "triggers", manifestError.Path)
+			assert.Equal(t, 5, manifestError.Line)
+		}
+	})
+
+	t.Run("ReturnsErrorWithIncludeFileForUnknownStageProperty", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/typo.yaml
+stages:
+  build:
+    image: golang`, mapResolver{"ci/typo.yaml": `
+stages:
+  push:
+    imag: extensions/docker:stable`}, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "ci/typo.yaml", manifestError.File)
+			assert.Equal(t, "stages.push", manifestError.Path)
+		}
+	})
+
+	t.Run("ReturnsErrorAtIncludeIfIncludeCannotBeResolved", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+include:
+- ci/push.yaml
+- ci/missing.yaml`, resolver, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "include[1]", manifestError.Path)
+			assert.Equal(t, 7, manifestError.Line)
+		}
+	})
+
+	t.Run("ReturnsErrorForIncludeCycle", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ci/a.yaml
+stages:
+  build:
+    image: golang`, mapResolver{
+			"ci/a.yaml": "include:\n- b.yaml",
+			"ci/b.yaml": "include:\n- a.yaml",
+		}, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "ci/b.yaml", manifestError.File)
+			assert.Equal(t, "include[0]", manifestError.Path)
+			assert.Contains(t, manifestError.Error(), "ci/a.yaml > ci/b.yaml > ci/a.yaml")
+		}
+	})
+
+	t.Run("ReturnsErrorForIncludeOutsideOfRepository", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestWithResolver(GetDefaultManifestPreferences(), `
+include:
+- ../other-repo/push.yaml
+stages:
+  build:
+    image: golang`, resolver, true)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForIncludesWithoutResolver", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+include:
+- ci/push.yaml
+stages:
+  build:
+    image: golang`, true)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestReadManifestFromFileWithIncludes(t *testing.T) {
+	t.Run("ReadsIncludesRelativeToManifestAndIncludingFragment", func(t *testing.T) {
+
+		dir := t.TempDir()
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, "ci"), 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, ".ziplinee.yaml"), []byte(`
+include:
+- ci/push.yaml
+stages:
+  build:
+    image: golang`), 0644))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "ci", "push.yaml"), []byte(`
+include:
+- notify.yaml
+stages:
+  push:
+    image: extensions/docker:stable
+    action: push`), 0644))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "ci", "notify.yaml"), []byte(`
+stages:
+  notify:
+    image: extensions/slack-build-status:stable`), 0644))
+
+		// act
+		manifest, err := ReadManifestFromFile(GetDefaultManifestPreferences(), filepath.Join(dir, ".ziplinee.yaml"), true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 3, len(manifest.Stages)) {
+			assert.Equal(t, "build", manifest.Stages[0].Name)
+			assert.Equal(t, "push", manifest.Stages[1].Name)
+			assert.Equal(t, "notify", manifest.Stages[2].Name)
+		}
+	})
+
+	t.Run("MarshalsManifestWithMergedIncludesThatCanBeReadWithoutResolver", func(t *testing.T) {
+
+		dir := t.TempDir()
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, "ci"), 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, ".ziplinee.yaml"), []byte(`
+include:
+- ci/shared.yaml
+labels:
+  app: ziplinee-ci-api
+stages:
+  build:
+    image: golang`), 0644))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "ci", "shared.yaml"), []byte(`
+env:
+  REGISTRY: ziplineeci
+labels:
+  team: ziplinee-team
+stages:
+  push:
+    image: extensions/docker:stable
+    action: push`), 0644))
+		manifest, err := ReadManifestFromFile(GetDefaultManifestPreferences(), filepath.Join(dir, ".ziplinee.yaml"), true)
+		assert.Nil(t, err)
+
+		// act
+		data, err := yaml.Marshal(manifest)
+
+		assert.Nil(t, err)
+		assert.NotContains(t, string(data), "include:")
+		reread, err := ReadManifest(GetDefaultManifestPreferences(), string(data), true)
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(reread.Stages)) {
+			assert.Equal(t, "build", reread.Stages[0].Name)
+			assert.Equal(t, "push", reread.Stages[1].Name)
+		}
+		assert.Equal(t, "ziplineeci", reread.GlobalEnvVars["REGISTRY"])
+		assert.Equal(t, "ziplinee-team", reread.Labels["team"])
+	})
+}
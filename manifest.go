@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"regexp"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jinzhu/copier"
@@ -15,6 +16,7 @@ import (
 
 // ZiplineeManifest is the object that the .ziplinee.yaml deserializes to
 type ZiplineeManifest struct {
+	Includes         []string                   `yaml:"include,omitempty" json:",omitempty"`
 	Archived         bool                       `yaml:"archived,omitempty"`
 	Builder          ZiplineeBuilder            `yaml:"builder,omitempty"`
 	Labels           map[string]string          `yaml:"labels,omitempty"`
@@ -22,6 +24,7 @@ type ZiplineeManifest struct {
 	GlobalEnvVars    map[string]string          `yaml:"env,omitempty"`
 	Triggers         []*ZiplineeTrigger         `yaml:"triggers,omitempty"`
 	Stages           []*ZiplineeStage           `yaml:"-"`
+	StageTemplates   []*ZiplineeStage           `yaml:"-" json:",omitempty"`
 	Releases         []*ZiplineeRelease         `yaml:"-"`
 	ReleaseTemplates []*ZiplineeReleaseTemplate `yaml:"-"`
 	Bots             []*ZiplineeBot             `yaml:"-"`
@@ -31,6 +34,7 @@ type ZiplineeManifest struct {
 func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
 
 	var aux struct {
+		Includes            []string           `yaml:"include"`
 		Archived            bool               `yaml:"archived"`
 		Builder             ZiplineeBuilder    `yaml:"builder"`
 		Labels              map[string]string  `yaml:"labels"`
@@ -39,6 +43,7 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 		DeprecatedPipelines yaml.MapSlice      `yaml:"pipelines"`
 		Triggers            []*ZiplineeTrigger `yaml:"triggers"`
 		Stages              yaml.MapSlice      `yaml:"stages"`
+		StageTemplates      yaml.MapSlice      `yaml:"stageTemplates"`
 		Releases            yaml.MapSlice      `yaml:"releases"`
 		ReleaseTemplates    yaml.MapSlice      `yaml:"releaseTemplates"`
 		Bots                yaml.MapSlice      `yaml:"bots"`
@@ -50,6 +55,7 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 	}
 
 	// map auxiliary properties
+	c.Includes = aux.Includes
 	c.Archived = aux.Archived
 	c.Builder = aux.Builder
 	c.Version = aux.Version
@@ -58,8 +64,10 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 	c.Triggers = aux.Triggers
 
 	// provide backwards compatibility for the deprecated pipelines section now renamed to stages
+	stagesSection := "stages"
 	if len(aux.Stages) == 0 && len(aux.DeprecatedPipelines) > 0 {
 		aux.Stages = aux.DeprecatedPipelines
+		stagesSection = "pipelines"
 	}
 
 	for _, mi := range aux.Stages {
@@ -71,7 +79,7 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 
 		var stage *ZiplineeStage
 		if err := yaml.Unmarshal(bytes, &stage); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("%v.%v", stagesSection, mi.Key), err)
 		}
 		if stage == nil {
 			stage = &ZiplineeStage{}
@@ -83,6 +91,25 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 		c.Stages = append(c.Stages, stage)
 	}
 
+	for _, mi := range aux.StageTemplates {
+
+		bytes, err := yaml.Marshal(mi.Value)
+		if err != nil {
+			return err
+		}
+
+		var stageTemplate *ZiplineeStage
+		if err := yaml.Unmarshal(bytes, &stageTemplate); err != nil {
+			return wrapManifestError(fmt.Sprintf("stageTemplates.%v", mi.Key), err)
+		}
+		if stageTemplate == nil {
+			stageTemplate = &ZiplineeStage{}
+		}
+
+		stageTemplate.Name = mi.Key.(string)
+		c.StageTemplates = append(c.StageTemplates, stageTemplate)
+	}
+
 	releaseTemplates := map[string]*ZiplineeReleaseTemplate{}
 
 	for _, mi := range aux.ReleaseTemplates {
@@ -94,7 +121,7 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 
 		var releaseTemplate *ZiplineeReleaseTemplate
 		if err := yaml.Unmarshal(bytes, &releaseTemplate); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("releaseTemplates.%v", mi.Key), err)
 		}
 		if releaseTemplate == nil {
 			releaseTemplate = &ZiplineeReleaseTemplate{}
@@ -117,7 +144,7 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 
 		var release *ZiplineeRelease
 		if err := yaml.Unmarshal(bytes, &release); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("releases.%v", mi.Key), err)
 		}
 		if release == nil {
 			release = &ZiplineeRelease{}
@@ -141,21 +168,30 @@ func (c *ZiplineeManifest) UnmarshalYAML(unmarshal func(interface{}) error) (err
 
 		var bot *ZiplineeBot
 		if err := yaml.Unmarshal(bytes, &bot); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("bots.%v", mi.Key), err)
 		}
 		if bot == nil {
 			bot = &ZiplineeBot{}
 		}
 
 		bot.Name = mi.Key.(string)
+
+		bot.InitFromTemplate(releaseTemplates)
+
 		c.Bots = append(c.Bots, bot)
 	}
 
+	// stages using a stage template get the values of the template they don't set themselves, after release templates have been
+	// applied so stages from release templates can use stage templates as well
+	c.initStagesFromTemplates()
+
 	return nil
 }
 
 // MarshalYAML customizes marshalling an ZiplineeManifest
 func (c ZiplineeManifest) MarshalYAML() (out interface{}, err error) {
+	// includes aren't written, they've already been merged into the manifest when it was read and would be merged again or fail
+	// to resolve when reading the marshalled manifest
 	var aux struct {
 		Archived         bool               `yaml:"archived,omitempty"`
 		Builder          ZiplineeBuilder    `yaml:"builder,omitempty"`
@@ -164,6 +200,7 @@ func (c ZiplineeManifest) MarshalYAML() (out interface{}, err error) {
 		GlobalEnvVars    map[string]string  `yaml:"env,omitempty"`
 		Triggers         []*ZiplineeTrigger `yaml:"triggers,omitempty"`
 		Stages           yaml.MapSlice      `yaml:"stages,omitempty"`
+		StageTemplates   yaml.MapSlice      `yaml:"stageTemplates,omitempty"`
 		Releases         yaml.MapSlice      `yaml:"releases,omitempty"`
 		ReleaseTemplates yaml.MapSlice      `yaml:"releaseTemplates,omitempty"`
 		Bots             yaml.MapSlice      `yaml:"bots,omitempty"`
@@ -182,7 +219,25 @@ func (c ZiplineeManifest) MarshalYAML() (out interface{}, err error) {
 			Value: stage,
 		})
 	}
+	for _, stageTemplate := range c.StageTemplates {
+		aux.StageTemplates = append(aux.StageTemplates, yaml.MapItem{
+			Key:   stageTemplate.Name,
+			Value: stageTemplate,
+		})
+	}
+	releaseTemplates := map[string]*ZiplineeReleaseTemplate{}
+	for _, t := range c.ReleaseTemplates {
+		releaseTemplates[t.Name] = t
+	}
 	for _, release := range c.Releases {
+		if release.Template != "" && resolveReleaseTemplate(release.Template, releaseTemplates) != nil {
+			// the template has already been applied to the release, applying it again when reading the manifest would bring back
+			// the template stages the release removed
+			resolved := *release
+			resolved.Template = ""
+			resolved.TemplateParams = nil
+			release = &resolved
+		}
 		aux.Releases = append(aux.Releases, yaml.MapItem{
 			Key:   release.Name,
 			Value: release,
@@ -195,6 +250,13 @@ func (c ZiplineeManifest) MarshalYAML() (out interface{}, err error) {
 		})
 	}
 	for _, bot := range c.Bots {
+		if bot.Template != "" && resolveReleaseTemplate(bot.Template, releaseTemplates) != nil {
+			// same as for releases, the template has already been applied to the bot
+			resolved := *bot
+			resolved.Template = ""
+			resolved.TemplateParams = nil
+			bot = &resolved
+		}
 		aux.Bots = append(aux.Bots, yaml.MapItem{
 			Key:   bot.Name,
 			Value: bot,
@@ -230,6 +292,12 @@ func (c *ZiplineeManifest) SetDefaults(preferences ZiplineeManifestPreferences)
 		for _, t := range r.Triggers {
 			t.SetDefaults(preferences, TriggerTypeRelease, r.Name)
 		}
+		for _, i := range r.Inputs {
+			i.SetDefaults()
+		}
+		if r.Concurrency != nil {
+			r.Concurrency.SetDefaults()
+		}
 		for _, s := range r.Stages {
 			s.SetDefaults(*r.Builder)
 		}
@@ -249,100 +317,162 @@ func (c *ZiplineeManifest) SetDefaults(preferences ZiplineeManifestPreferences)
 		for _, t := range b.Triggers {
 			t.SetDefaults(preferences, TriggerTypeBot, b.Name)
 		}
+		for _, i := range b.Inputs {
+			i.SetDefaults()
+		}
+		if b.Concurrency != nil {
+			b.Concurrency.SetDefaults()
+		}
 		for _, s := range b.Stages {
 			s.SetDefaults(*b.Builder)
 		}
 	}
 }
 
-// Validate checks if the manifest is valid
+// Validate checks if the manifest is valid and returns all errors combined in a ValidationResult
 func (c *ZiplineeManifest) Validate(preferences ZiplineeManifestPreferences) (err error) {
+	return c.ValidateAll(preferences).Err()
+}
 
-	err = c.Builder.validate(preferences)
-	if err != nil {
-		return
+// ValidateAll checks the manifest and collects all errors and warnings instead of stopping at the first one
+func (c *ZiplineeManifest) ValidateAll(preferences ZiplineeManifestPreferences) (result *ValidationResult) {
+
+	result = &ValidationResult{}
+
+	result.addError(ValidationCodeBuilderInvalid, "builder", c.Builder.validate(preferences))
+
+	// loop labels in a fixed order and check if they meet the label regexes
+	labelKeys := make([]string, 0, len(c.Labels))
+	for key := range c.Labels {
+		labelKeys = append(labelKeys, key)
 	}
+	sort.Strings(labelKeys)
 
-	// loop labels and check if they meet the label regexes
-	for key, value := range c.Labels {
+	for _, key := range labelKeys {
 		if pattern, ok := preferences.LabelRegexes[key]; ok {
 			pattern = fmt.Sprintf("^%v$", strings.TrimSpace(pattern))
 
-			match, err := regexp.MatchString(pattern, value)
+			match, err := compiledRegexes.MatchString(pattern, c.Labels[key])
 			if err != nil {
-				return err
-			}
-
-			if !match {
-				return fmt.Errorf("Label %v does not match regex %v", key, pattern)
+				result.addError(ValidationCodeLabelInvalid, fmt.Sprintf("labels.%v", key), err)
+			} else if !match {
+				result.addError(ValidationCodeLabelInvalid, fmt.Sprintf("labels.%v", key), fmt.Errorf("Label %v does not match regex %v", key, pattern))
 			}
 		}
 	}
 
 	if len(c.Stages) == 0 {
-		return fmt.Errorf("The manifest should define 1 or more stages")
+		result.addError(ValidationCodeStagesMissing, "", fmt.Errorf("The manifest should define 1 or more stages"))
 	}
-	for _, s := range c.Stages {
-		err = s.Validate()
-		if err != nil {
-			return
+	c.validateStages(result, "stages", c.Stages)
+
+	for _, s := range c.StageTemplates {
+		if s.Template != "" {
+			result.addError(ValidationCodeTemplateInvalid, fmt.Sprintf("stageTemplates.%v.template", s.Name), fmt.Errorf("Stage template %v can't use a template itself", s.Name))
 		}
 	}
 
-	for _, t := range c.Triggers {
-		err = t.Validate("build", "")
-		if err != nil {
-			return
+	for i, t := range c.Triggers {
+		result.addError(ValidationCodeTriggerInvalid, fmt.Sprintf("triggers[%v]", i), t.Validate(TriggerTypeBuild, ""))
+	}
+
+	releaseTemplates := map[string]*ZiplineeReleaseTemplate{}
+	for _, t := range c.ReleaseTemplates {
+		releaseTemplates[t.Name] = t
+	}
+	for _, t := range c.ReleaseTemplates {
+		templatePath := fmt.Sprintf("releaseTemplates.%v", t.Name)
+		if err := t.validateExtends(releaseTemplates); err != nil {
+			result.addError(ValidationCodeTemplateInvalid, templatePath, err)
+			continue
 		}
+		// placeholders can use parameters declared in the templates it extends
+		result.addError(ValidationCodeTemplateInvalid, templatePath, resolveReleaseTemplate(t.Name, releaseTemplates).validateParameters())
 	}
 
 	for _, r := range c.Releases {
-		if r.Builder != nil {
-			err = r.Builder.validate(preferences)
-			if err != nil {
-				return
-			}
+		// builders equal to the manifest builder have already been validated
+		if r.Builder != nil && *r.Builder != c.Builder {
+			result.addError(ValidationCodeBuilderInvalid, fmt.Sprintf("releases.%v.builder", r.Name), r.Builder.validate(preferences))
 		}
 
-		for _, t := range r.Triggers {
-			err = t.Validate(TriggerTypeRelease, r.Name)
-			if err != nil {
-				return
-			}
+		for i, t := range r.Triggers {
+			result.addError(ValidationCodeTriggerInvalid, fmt.Sprintf("releases.%v.triggers[%v]", r.Name, i), t.Validate(TriggerTypeRelease, r.Name))
 		}
-
-		for _, s := range r.Stages {
-			err = s.Validate()
-			if err != nil {
-				return
-			}
+		result.addError(ValidationCodeInputInvalid, fmt.Sprintf("releases.%v", r.Name), validateManualInputs(r.Inputs))
+		result.addError(ValidationCodeTemplateInvalid, fmt.Sprintf("releases.%v", r.Name), validateTemplateParams(r.Template, r.TemplateParams, releaseTemplates))
+		if r.Concurrency != nil {
+			result.addError(ValidationCodeConcurrencyInvalid, fmt.Sprintf("releases.%v.concurrency", r.Name), r.Concurrency.Validate())
 		}
+
+		c.validateStages(result, fmt.Sprintf("releases.%v.stages", r.Name), r.Stages)
 	}
 
 	for _, b := range c.Bots {
-		if b.Builder != nil {
-			err = b.Builder.validate(preferences)
-			if err != nil {
-				return
-			}
+		// builders equal to the manifest builder have already been validated
+		if b.Builder != nil && *b.Builder != c.Builder {
+			result.addError(ValidationCodeBuilderInvalid, fmt.Sprintf("bots.%v.builder", b.Name), b.Builder.validate(preferences))
 		}
 
-		for _, t := range b.Triggers {
-			err = t.Validate(TriggerTypeBot, b.Name)
-			if err != nil {
-				return
-			}
+		for i, t := range b.Triggers {
+			result.addError(ValidationCodeTriggerInvalid, fmt.Sprintf("bots.%v.triggers[%v]", b.Name, i), t.Validate(TriggerTypeBot, b.Name))
+		}
+		result.addError(ValidationCodeInputInvalid, fmt.Sprintf("bots.%v", b.Name), validateManualInputs(b.Inputs))
+		result.addError(ValidationCodeTemplateInvalid, fmt.Sprintf("bots.%v", b.Name), validateTemplateParams(b.Template, b.TemplateParams, releaseTemplates))
+		if b.Concurrency != nil {
+			result.addError(ValidationCodeConcurrencyInvalid, fmt.Sprintf("bots.%v.concurrency", b.Name), b.Concurrency.Validate())
 		}
 
-		for _, s := range b.Stages {
-			err = s.Validate()
-			if err != nil {
-				return
+		c.validateStages(result, fmt.Sprintf("bots.%v.stages", b.Name), b.Stages)
+	}
+
+	return
+}
+
+func (c *ZiplineeManifest) validateStages(result *ValidationResult, path string, stages []*ZiplineeStage) {
+	for _, s := range stages {
+		stagePath := fmt.Sprintf("%v.%v", path, s.Name)
+
+		if err := validateStageMerge(s); err != nil {
+			result.addError(ValidationCodeStageInvalid, stagePath, err)
+			continue
+		}
+		if err := c.validateStageTemplate(s); err != nil {
+			result.addError(ValidationCodeTemplateInvalid, stagePath, err)
+			continue
+		}
+		result.addError(ValidationCodeStageInvalid, stagePath, s.Validate())
+		validateWhen(result, stagePath+".when", "Stage", s.Name, s.When)
+
+		for i, svc := range s.Services {
+			servicePath := fmt.Sprintf("%v.services[%v]", stagePath, i)
+			validateWhen(result, servicePath+".when", "Service", svc.Name, svc.When)
+			if svc.Readiness != nil && svc.Readiness.usesDeprecatedProperties() {
+				result.addWarning(ValidationCodeReadinessDeprecated, servicePath+".readiness", fmt.Errorf("Service %v uses deprecated readiness properties path, port, protocol and hostname, use httpGet instead", svc.Name))
+			}
+			if svc.ReadinessProbe != nil && svc.ReadinessProbe.usesDeprecatedProperties() {
+				result.addWarning(ValidationCodeReadinessDeprecated, servicePath+".readinessProbe", fmt.Errorf("Service %v uses deprecated readinessProbe properties path, port, protocol and hostname, use httpGet instead", svc.Name))
 			}
 		}
+
+		c.validateStages(result, stagePath+".parallelStages", s.ParallelStages)
 	}
+}
 
-	return nil
+// validateWhen adds an error for a when expression that doesn't parse and a warning for each variable that isn't in the WhenContext
+func validateWhen(result *ValidationResult, path, kind, name, when string) {
+	if when == "" {
+		return
+	}
+
+	expression, err := ParseWhenExpression(when)
+	if err != nil {
+		result.addError(ValidationCodeWhenInvalid, path, fmt.Errorf("%v %v has an invalid when expression: %v", kind, name, err))
+		return
+	}
+	for _, variable := range expression.UnknownVariables() {
+		result.addWarning(ValidationCodeWhenUnknownVariable, path, fmt.Errorf("%v %v uses unknown variable %v in its when expression, use status, branch, server, action, labels or env", kind, name, variable))
+	}
 }
 
 // GetAllTriggers returns both build and release triggers as one list
@@ -383,6 +513,71 @@ func (c *ZiplineeManifest) GetAllTriggers(repoSource, repoOwner, repoName string
 	return triggers
 }
 
+// MatchingTriggers returns all build, release and bot triggers that fire for the event, together with the action they result in;
+// 'all' triggers get no events before it, use MatchingTriggersForEvents to pass them
+func (c *ZiplineeManifest) MatchingTriggers(repoSource, repoOwner, repoName string, event *ZiplineeEvent) []ZiplineeTriggerMatch {
+	return c.MatchingTriggersForEvents(repoSource, repoOwner, repoName, []*ZiplineeEvent{event})
+}
+
+// MatchingTriggersForEvents returns all build, release and bot triggers that fire for the last event in the stream, taking the events
+// before it into account for 'all' triggers; the events are ordered by the time they happened
+func (c *ZiplineeManifest) MatchingTriggersForEvents(repoSource, repoOwner, repoName string, events []*ZiplineeEvent) []ZiplineeTriggerMatch {
+	matches := make([]ZiplineeTriggerMatch, 0)
+
+	pipelineName := fmt.Sprintf("%v/%v/%v", repoSource, repoOwner, repoName)
+
+	// check all build triggers
+	for _, t := range c.Triggers {
+		if t != nil {
+			t.ReplaceSelf(pipelineName)
+			if t.FiresForEvents(events) {
+				matches = append(matches, ZiplineeTriggerMatch{
+					Type:        TriggerTypeBuild,
+					Trigger:     t,
+					BuildAction: t.BuildAction,
+					Concurrency: resolveConcurrency(t, nil, pipelineName, TriggerTypeBuild, ""),
+				})
+			}
+		}
+	}
+
+	// check all release triggers
+	for _, r := range c.Releases {
+		for _, t := range r.Triggers {
+			if t != nil {
+				t.ReplaceSelf(pipelineName)
+				if t.FiresForEvents(events) {
+					matches = append(matches, ZiplineeTriggerMatch{
+						Type:          TriggerTypeRelease,
+						Trigger:       t,
+						ReleaseAction: t.ReleaseAction,
+						Concurrency:   resolveConcurrency(t, r.Concurrency, pipelineName, TriggerTypeRelease, r.Name),
+					})
+				}
+			}
+		}
+	}
+
+	// check all bot triggers
+	for _, b := range c.Bots {
+		for _, t := range b.Triggers {
+			if t != nil {
+				t.ReplaceSelf(pipelineName)
+				if t.FiresForEvents(events) {
+					matches = append(matches, ZiplineeTriggerMatch{
+						Type:        TriggerTypeBot,
+						Trigger:     t,
+						BotAction:   t.BotAction,
+						Concurrency: resolveConcurrency(t, b.Concurrency, pipelineName, TriggerTypeBot, b.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
 // DeepCopy provides a copy of all nested pointers
 func (c *ZiplineeManifest) DeepCopy() (target ZiplineeManifest) {
 
@@ -403,35 +598,19 @@ func Exists(manifestPath string) bool {
 	return true
 }
 
-// ReadManifestFromFile reads the .ziplinee.yaml into an ZiplineeManifest object
+// ReadManifestFromFile reads the .ziplinee.yaml into an ZiplineeManifest object, with includes read relative to the directory of the file
 func ReadManifestFromFile(preferences *ZiplineeManifestPreferences, manifestPath string, validate bool) (manifest ZiplineeManifest, err error) {
 
 	log.Debug().Msgf("Reading %v file...", manifestPath)
 
-	// default preferences if not passed
-	if preferences == nil {
-		preferences = GetDefaultManifestPreferences()
-	}
-
 	data, err := ioutil.ReadFile(manifestPath)
 	if err != nil {
 		return manifest, err
 	}
 
-	// unmarshal strict, so non-defined properties or incorrect nesting will fail
-	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
-		return manifest, err
-	}
-
-	// set defaults
-	manifest.SetDefaults(*preferences)
-
-	if validate {
-		// check if manifest is valid
-		err = manifest.Validate(*preferences)
-		if err != nil {
-			return manifest, err
-		}
+	manifest, err = readManifest(preferences, data, manifestPath, &FileResolver{Dir: filepath.Dir(manifestPath)}, validate)
+	if err != nil {
+		return
 	}
 
 	log.Debug().Msgf("Finished reading %v file successfully", manifestPath)
@@ -441,6 +620,15 @@ func ReadManifestFromFile(preferences *ZiplineeManifestPreferences, manifestPath
 
 // ReadManifest reads the string representation of .ziplinee.yaml into an ZiplineeManifest object
 func ReadManifest(preferences *ZiplineeManifestPreferences, manifestString string, validate bool) (manifest ZiplineeManifest, err error) {
+	return readManifest(preferences, []byte(manifestString), "", nil, validate)
+}
+
+// ReadManifestWithResolver reads the string representation of .ziplinee.yaml into an ZiplineeManifest object, using the resolver to read its includes
+func ReadManifestWithResolver(preferences *ZiplineeManifestPreferences, manifestString string, resolver Resolver, validate bool) (manifest ZiplineeManifest, err error) {
+	return readManifest(preferences, []byte(manifestString), "", resolver, validate)
+}
+
+func readManifest(preferences *ZiplineeManifestPreferences, data []byte, file string, resolver Resolver, validate bool) (manifest ZiplineeManifest, err error) {
 
 	// default preferences if not passed
 	if preferences == nil {
@@ -448,8 +636,16 @@ func ReadManifest(preferences *ZiplineeManifestPreferences, manifestString strin
 	}
 
 	// unmarshal strict, so non-defined properties or incorrect nesting will fail
-	if err := yaml.UnmarshalStrict([]byte(manifestString), &manifest); err != nil {
-		return manifest, err
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return manifest, locateManifestError(data, file, err)
+	}
+
+	sources := newManifestSources(file, data)
+	if len(manifest.Includes) > 0 {
+		manifest, err = sources.includeManifestFragments(resolver)
+		if err != nil {
+			return manifest, err
+		}
 	}
 
 	// set defaults
@@ -459,7 +655,7 @@ func ReadManifest(preferences *ZiplineeManifestPreferences, manifestString strin
 		// check if manifest is valid
 		err = manifest.Validate(*preferences)
 		if err != nil {
-			return manifest, err
+			return manifest, sources.locateError(err)
 		}
 	}
 
@@ -0,0 +1,196 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ManifestError is a parse or validation error that knows where in the manifest it originates from
+type ManifestError struct {
+	File   string
+	Line   int
+	Column int
+	Path   string
+	Err    error
+}
+
+// Error returns the error message prefixed with the location in the manifest if known
+func (e *ManifestError) Error() string {
+	if e.Line > 0 {
+		location := fmt.Sprintf("line %v", e.Line)
+		if e.File != "" {
+			location = fmt.Sprintf("%v:%v", e.File, e.Line)
+			if e.Column > 0 {
+				location = fmt.Sprintf("%v:%v", location, e.Column)
+			}
+		} else if e.Column > 0 {
+			location = fmt.Sprintf("%v, column %v", location, e.Column)
+		}
+		return fmt.Sprintf("%v: %v", location, e.Err)
+	}
+	if e.File != "" {
+		return fmt.Sprintf("%v: %v", e.File, e.Err)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	yamlTypeErrorLineRegex   = regexp.MustCompile(`^line (\d+): `)
+	yamlSyntaxErrorLineRegex = regexp.MustCompile(`^yaml: line (\d+): `)
+)
+
+// wrapManifestError prefixes the path of the error with the path of the parent node, so errors can be wrapped on their way up
+func wrapManifestError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if manifestError, ok := err.(*ManifestError); ok {
+		if path == "" {
+			path = manifestError.Path
+		} else if manifestError.Path != "" {
+			if strings.HasPrefix(manifestError.Path, "[") {
+				path += manifestError.Path
+			} else {
+				path = fmt.Sprintf("%v.%v", path, manifestError.Path)
+			}
+		}
+		return &ManifestError{
+			Path: path,
+			Err:  manifestError.Err,
+		}
+	}
+
+	if typeError, ok := err.(*yaml.TypeError); ok {
+		// nested sections get unmarshalled from a re-marshalled fragment, so the line numbers yaml reports are meaningless
+		errors := make([]string, len(typeError.Errors))
+		for i, e := range typeError.Errors {
+			errors[i] = yamlTypeErrorLineRegex.ReplaceAllString(e, "")
+		}
+		err = &yaml.TypeError{Errors: errors}
+	}
+
+	return &ManifestError{
+		Path: path,
+		Err:  err,
+	}
+}
+
+// locateManifestError sets file, line and column for an error by looking up its path in the raw manifest
+func locateManifestError(data []byte, file string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var document yamlv3.Node
+	if yamlv3.Unmarshal(data, &document) != nil {
+		document = yamlv3.Node{}
+	}
+
+	if result, ok := err.(*ValidationResult); ok {
+		for _, p := range result.Problems {
+			locate(&document, file, p.ManifestError)
+		}
+		return result
+	}
+
+	manifestError, ok := err.(*ManifestError)
+	if !ok {
+		manifestError = &ManifestError{
+			Err: err,
+		}
+
+		// errors for the top level document come with a correct line number
+		if typeError, ok := err.(*yaml.TypeError); ok && len(typeError.Errors) > 0 {
+			if match := yamlTypeErrorLineRegex.FindStringSubmatch(typeError.Errors[0]); len(match) > 1 {
+				manifestError.Line, _ = strconv.Atoi(match[1])
+			}
+		} else if match := yamlSyntaxErrorLineRegex.FindStringSubmatch(err.Error()); len(match) > 1 {
+			manifestError.Line, _ = strconv.Atoi(match[1])
+		}
+	}
+
+	locate(&document, file, manifestError)
+
+	return manifestError
+}
+
+func locate(document *yamlv3.Node, file string, manifestError *ManifestError) {
+	manifestError.File = file
+
+	if manifestError.Path != "" && manifestError.Line == 0 {
+		node := findNodeForPath(document, manifestError.Path)
+		if node == nil && (manifestError.Path == "stages" || strings.HasPrefix(manifestError.Path, "stages.")) {
+			// manifests can still use the deprecated pipelines section instead of stages
+			node = findNodeForPath(document, "pipelines"+strings.TrimPrefix(manifestError.Path, "stages"))
+		}
+		if node != nil {
+			manifestError.Line = node.Line
+			manifestError.Column = node.Column
+		}
+	}
+}
+
+// findNodeForPath returns the deepest node that matches a path like releases.staging.triggers[0]; for mapping entries it returns the key node
+func findNodeForPath(node *yamlv3.Node, path string) (found *yamlv3.Node) {
+	if node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+
+	for path != "" {
+		switch node.Kind {
+		case yamlv3.MappingNode:
+			path = strings.TrimPrefix(path, ".")
+
+			// keys can contain dots themselves, so pick the longest key matching the start of the path
+			var key, value *yamlv3.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				k := node.Content[i].Value
+				if (path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[")) && (key == nil || len(k) > len(key.Value)) {
+					key, value = node.Content[i], node.Content[i+1]
+				}
+			}
+			if key == nil {
+				return
+			}
+			found = key
+			node = value
+			path = strings.TrimPrefix(path, key.Value)
+
+		case yamlv3.SequenceNode:
+			if !strings.HasPrefix(path, "[") {
+				return
+			}
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return
+			}
+			index, err := strconv.Atoi(path[1:end])
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return
+			}
+			node = node.Content[index]
+			found = node
+			path = path[end+1:]
+
+		default:
+			return
+		}
+	}
+
+	return
+}
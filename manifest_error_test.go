@@ -0,0 +1,178 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestManifestError(t *testing.T) {
+	t.Run("ReturnsFileLineAndColumnInErrorMessage", func(t *testing.T) {
+
+		err := &ManifestError{File: ".ziplinee.yaml", Line: 12, Column: 5, Err: fmt.Errorf("Stage build has no image set")}
+
+		// act
+		message := err.Error()
+
+		assert.Equal(t, ".ziplinee.yaml:12:5: Stage build has no image set", message)
+	})
+
+	t.Run("ReturnsLineAndColumnInErrorMessageIfFileIsUnknown", func(t *testing.T) {
+
+		err := &ManifestError{Line: 12, Column: 5, Err: fmt.Errorf("Stage build has no image set")}
+
+		// act
+		message := err.Error()
+
+		assert.Equal(t, "line 12, column 5: Stage build has no image set", message)
+	})
+
+	t.Run("ReturnsUnderlyingErrorMessageIfNotLocated", func(t *testing.T) {
+
+		err := &ManifestError{Path: "stages.build", Err: fmt.Errorf("Stage build has no image set")}
+
+		// act
+		message := err.Error()
+
+		assert.Equal(t, "Stage build has no image set", message)
+	})
+}
+
+func TestWrapManifestError(t *testing.T) {
+	t.Run("PrefixesPathOfNestedManifestError", func(t *testing.T) {
+
+		err := wrapManifestError("parallelStages.a", fmt.Errorf("some error"))
+
+		// act
+		err = wrapManifestError("stages.build", err)
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, "stages.build.parallelStages.a", manifestError.Path)
+	})
+
+	t.Run("AppendsIndexWithoutDot", func(t *testing.T) {
+
+		err := wrapManifestError("[1]", fmt.Errorf("some error"))
+
+		// act
+		err = wrapManifestError("triggers", err)
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, "triggers[1]", manifestError.Path)
+	})
+
+	t.Run("StripsLineNumbersFromNestedYamlTypeErrors", func(t *testing.T) {
+
+		err := &yaml.TypeError{Errors: []string{"line 2: cannot unmarshal !!str `foo` into []string"}}
+
+		// act
+		wrapped := wrapManifestError("stages.build", err)
+
+		assert.Equal(t, "yaml: unmarshal errors:\n  cannot unmarshal !!str `foo` into []string", wrapped.Error())
+	})
+
+	t.Run("ReturnsNilForNilError", func(t *testing.T) {
+
+		// act
+		err := wrapManifestError("stages.build", nil)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestLocateManifestError(t *testing.T) {
+
+	data := []byte(`labels:
+  app.kubernetes.io/name: my-app
+stages:
+  build:
+    image: golang
+releases:
+  staging:
+    triggers:
+    - pipeline:
+        name: self
+    - git:
+        repository: self
+`)
+
+	t.Run("ReturnsPositionOfMappingKey", func(t *testing.T) {
+
+		// act
+		err := locateManifestError(data, ".ziplinee.yaml", &ManifestError{Path: "stages.build", Err: fmt.Errorf("some error")})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, ".ziplinee.yaml", manifestError.File)
+		assert.Equal(t, 4, manifestError.Line)
+		assert.Equal(t, 3, manifestError.Column)
+	})
+
+	t.Run("ReturnsPositionOfSequenceItem", func(t *testing.T) {
+
+		// act
+		err := locateManifestError(data, "", &ManifestError{Path: "releases.staging.triggers[1]", Err: fmt.Errorf("some error")})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, 11, manifestError.Line)
+		assert.Equal(t, 7, manifestError.Column)
+	})
+
+	t.Run("ReturnsPositionOfKeyContainingDots", func(t *testing.T) {
+
+		// act
+		err := locateManifestError(data, "", &ManifestError{Path: "labels.app.kubernetes.io/name", Err: fmt.Errorf("some error")})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, 2, manifestError.Line)
+		assert.Equal(t, 3, manifestError.Column)
+	})
+
+	t.Run("ReturnsPositionOfDeepestExistingNodeIfPathIsNotFullyPresent", func(t *testing.T) {
+
+		// act
+		err := locateManifestError(data, "", &ManifestError{Path: "releases.staging.stages.deploy", Err: fmt.Errorf("some error")})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, 7, manifestError.Line)
+		assert.Equal(t, 3, manifestError.Column)
+	})
+
+	t.Run("ReturnsPositionOfStageInDeprecatedPipelinesSection", func(t *testing.T) {
+
+		data := []byte(`pipelines:
+  build:
+    image: golang
+  test:
+    commands:
+    - go test ./...
+`)
+
+		// act
+		err := locateManifestError(data, ".ziplinee.yaml", &ManifestError{Path: "stages.test", Err: fmt.Errorf("some error")})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, ".ziplinee.yaml", manifestError.File)
+		assert.Equal(t, 4, manifestError.Line)
+		assert.Equal(t, 3, manifestError.Column)
+	})
+
+	t.Run("ReturnsLineOfTopLevelYamlTypeError", func(t *testing.T) {
+
+		// act
+		err := locateManifestError(data, "", &yaml.TypeError{Errors: []string{"line 3: field unknown not found"}})
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, 3, manifestError.Line)
+	})
+}
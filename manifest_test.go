@@ -2,8 +2,10 @@ package manifest
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v2"
@@ -27,6 +29,18 @@ func TestReadManifestFromFile(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("ReturnsErrorWithFileAndLineForManifestWithUnknownSections", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifestFromFile(GetDefaultManifestPreferences(), "test-non-strict-manifest.yaml", true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "test-non-strict-manifest.yaml", manifestError.File)
+			assert.Equal(t, 1, manifestError.Line)
+		}
+	})
+
 	t.Run("ReturnsManifestWithArchivedTrueWithoutErrors", func(t *testing.T) {
 
 		// act
@@ -498,6 +512,305 @@ pipelines:
 			assert.Equal(t, "extensions/github-stale-issue-bot:stable", manifest.Bots[2].Stages[0].ContainerImage)
 		}
 	})
+
+	t.Run("ReturnsManifestWithBotTriggerFilters", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+bots:
+  deploy-bot:
+    triggers:
+    - github:
+        events:
+        - issue_comment
+        filters:
+        - path: comment.body
+          pattern: '(?s).*/deploy.*'
+    - pubsub:
+        project: my-project
+        topic: my-topic
+        filters:
+        - attribute: type
+          pattern: image-pushed
+    stages:
+      deploy:
+        image: alpine`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Bots)) && assert.Equal(t, 2, len(manifest.Bots[0].Triggers)) {
+			assert.Equal(t, "comment.body", manifest.Bots[0].Triggers[0].Github.Filters[0].Path)
+			assert.Equal(t, "(?s).*/deploy.*", manifest.Bots[0].Triggers[0].Github.Filters[0].Pattern)
+			assert.Equal(t, "type", manifest.Bots[0].Triggers[1].PubSub.Filters[0].Attribute)
+			assert.Equal(t, "image-pushed", manifest.Bots[0].Triggers[1].PubSub.Filters[0].Pattern)
+		}
+	})
+
+	t.Run("ReturnsManifestWithReleaseAndBotInputs", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    inputs:
+    - name: dry-run
+      type: bool
+      default: true
+    - name: replicas
+      type: number
+      values: [1, 3, 5]
+      required: true
+    stages:
+      deploy:
+        image: alpine
+bots:
+  announce:
+    inputs:
+    - name: message
+    stages:
+      post:
+        image: alpine`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.Equal(t, 2, len(manifest.Releases[0].Inputs)) {
+			assert.Equal(t, ManualInputTypeBool, manifest.Releases[0].Inputs[0].Type)
+			assert.Equal(t, "true", manifest.Releases[0].Inputs[0].Default)
+			assert.Equal(t, []string{"1", "3", "5"}, manifest.Releases[0].Inputs[1].Values)
+			assert.True(t, manifest.Releases[0].Inputs[1].Required)
+		}
+		if assert.Equal(t, 1, len(manifest.Bots)) && assert.Equal(t, 1, len(manifest.Bots[0].Inputs)) {
+			assert.Equal(t, ManualInputTypeString, manifest.Bots[0].Inputs[0].Type)
+		}
+	})
+
+	t.Run("ReturnsErrorForInvalidReleaseInput", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    inputs:
+    - name: environment
+      type: choice
+    stages:
+      deploy:
+        image: alpine`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "releases.production.inputs[0]", manifestError.Path)
+			assert.Equal(t, 8, manifestError.Line)
+		}
+	})
+
+	t.Run("ReturnsManifestWithConcurrencyOnTriggersReleasesAndBots", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- git:
+    repository: github.com/ziplineeci/ziplinee-ci-api
+  concurrency:
+    cancelInProgress: true
+    debounce: 30s
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    concurrency:
+      group: production-deploys
+    stages:
+      deploy:
+        image: alpine
+bots:
+  announce:
+    concurrency:
+      maxInFlight: 5
+    stages:
+      post:
+        image: alpine`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Triggers)) && assert.NotNil(t, manifest.Triggers[0].Concurrency) {
+			assert.True(t, manifest.Triggers[0].Concurrency.CancelInProgress)
+			assert.Equal(t, "30s", manifest.Triggers[0].Concurrency.Debounce)
+			assert.Equal(t, 1, manifest.Triggers[0].Concurrency.MaxInFlight)
+		}
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.NotNil(t, manifest.Releases[0].Concurrency) {
+			assert.Equal(t, "production-deploys", manifest.Releases[0].Concurrency.Group)
+			assert.Equal(t, 1, manifest.Releases[0].Concurrency.MaxInFlight)
+		}
+		if assert.Equal(t, 1, len(manifest.Bots)) && assert.NotNil(t, manifest.Bots[0].Concurrency) {
+			assert.Equal(t, 5, manifest.Bots[0].Concurrency.MaxInFlight)
+		}
+	})
+
+	t.Run("ReturnsErrorForInvalidReleaseConcurrency", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    concurrency:
+      maxInFlight: 2
+      cancelInProgress: true
+    stages:
+      deploy:
+        image: alpine`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeConcurrencyInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "releases.production.concurrency", result.Errors()[0].Path)
+			assert.Equal(t, 7, result.Errors()[0].Line)
+		}
+	})
+
+	t.Run("ReturnsManifestWithCompositeTriggers", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- any:
+    triggers:
+    - pipeline:
+        name: github.com/ziplineeci/ziplinee-ci-contracts
+    - pipeline:
+        name: github.com/ziplineeci/ziplinee-ci-foundation
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - all:
+        triggers:
+        - release:
+            name: self
+            target: staging
+        timeWindows:
+        - days: [mon-fri]
+          start: 09:00
+          end: 17:00
+          timezone: Europe/Amsterdam
+    stages:
+      deploy:
+        image: alpine`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Triggers)) && assert.Equal(t, 2, len(manifest.Triggers[0].Any.Triggers)) {
+			assert.Equal(t, "finished", manifest.Triggers[0].Any.Triggers[1].Pipeline.Event)
+			assert.Equal(t, "master|main", manifest.Triggers[0].Any.Triggers[1].Pipeline.Branch)
+			assert.Nil(t, manifest.Triggers[0].Any.Triggers[1].BuildAction)
+			assert.Equal(t, "master", manifest.Triggers[0].BuildAction.Branch)
+		}
+		if assert.Equal(t, 1, len(manifest.Releases[0].Triggers)) {
+			assert.Equal(t, "same", manifest.Releases[0].Triggers[0].ReleaseAction.Version)
+			assert.Equal(t, []string{"mon-fri"}, manifest.Releases[0].Triggers[0].All.TimeWindows[0].Days)
+		}
+	})
+
+	t.Run("ReturnsErrorForInvalidCompositeSubTrigger", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- all:
+    triggers:
+    - pipeline:
+        name: github.com/ziplineeci/ziplinee-ci-contracts
+    - cron:
+        schedule: '* * *'
+stages:
+  build:
+    image: golang`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "triggers[0].all.triggers[1].cron", result.Errors()[0].Path)
+			assert.Equal(t, 7, result.Errors()[0].Line)
+		}
+	})
+
+	t.Run("ReturnsManifestWithCronTriggerTimezone", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- cron:
+    schedule: '0 9 * * 1-5'
+    timezone: Europe/Amsterdam
+stages:
+  build:
+    image: golang`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Triggers)) {
+			assert.Equal(t, "Europe/Amsterdam", manifest.Triggers[0].Cron.Timezone)
+		}
+	})
+
+	t.Run("ReturnsErrorForUnknownCronTriggerTimezone", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- cron:
+    schedule: '0 9 * * 1-5'
+    timezone: Europe/Atlantis
+stages:
+  build:
+    image: golang`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "triggers[0].cron", manifestError.Path)
+			assert.Equal(t, 3, manifestError.Line)
+		}
+	})
+
+	t.Run("ReturnsManifestWithWebhookTrigger", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - webhook:
+        name: jira
+        headers:
+          X-Event-Type: issue_updated
+        filters:
+        - path: issue.fields.status.name
+          pattern: Approved
+    stages:
+      deploy:
+        image: alpine`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.Equal(t, 1, len(manifest.Releases[0].Triggers)) {
+			webhook := manifest.Releases[0].Triggers[0].Webhook
+			assert.Equal(t, "jira", webhook.Name)
+			assert.Equal(t, "issue_updated", webhook.Headers["X-Event-Type"])
+			assert.Equal(t, "issue.fields.status.name", webhook.Filters[0].Path)
+			assert.Equal(t, "production", manifest.Releases[0].Triggers[0].ReleaseAction.Target)
+		}
+	})
 }
 
 func TestVersion(t *testing.T) {
@@ -869,6 +1182,112 @@ stages:
 	})
 }
 
+func TestReadManifestErrorLocation(t *testing.T) {
+	t.Run("ReturnsLineAndColumnOfStageWithoutImage", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+  bake:
+    commands:
+    - docker build .`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "stages.bake", manifestError.Path)
+			assert.Equal(t, 5, manifestError.Line)
+			assert.Equal(t, 3, manifestError.Column)
+		}
+	})
+
+	t.Run("ReturnsLineAndColumnOfReleaseStageWithoutImage", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  staging:
+    stages:
+      deploy:
+        commands:
+        - kubectl apply`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "releases.staging.stages.deploy", manifestError.Path)
+			assert.Equal(t, 8, manifestError.Line)
+			assert.Equal(t, 7, manifestError.Column)
+		}
+	})
+
+	t.Run("ReturnsLineAndColumnOfInvalidTrigger", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+triggers:
+- git:
+    repository: github.com/ziplineeci/ziplinee-ci-manifest
+- pipeline:
+    event: unknown
+    name: self`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "triggers[1].pipeline", manifestError.Path)
+			assert.Equal(t, 8, manifestError.Line)
+			assert.Equal(t, 3, manifestError.Column)
+		}
+	})
+
+	t.Run("ReturnsLineAndColumnOfLabelNotMatchingRegex", func(t *testing.T) {
+
+		preferences := GetDefaultManifestPreferences()
+		preferences.LabelRegexes["type"] = "api|web"
+
+		// act
+		_, err := ReadManifest(preferences, `
+labels:
+  app: my-app
+  type: library
+stages:
+  build:
+    image: golang`, true)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "labels.type", manifestError.Path)
+			assert.Equal(t, 4, manifestError.Line)
+			assert.Equal(t, 3, manifestError.Column)
+		}
+	})
+
+	t.Run("ReturnsLineAndColumnOfStageFailingToUnmarshal", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+  bake:
+    image: docker
+    commands: 5`, false)
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "stages.bake", manifestError.Path)
+			assert.Equal(t, 5, manifestError.Line)
+			assert.Equal(t, 3, manifestError.Column)
+		}
+	})
+}
+
 func TestGetAllTriggers(t *testing.T) {
 	t.Run("ReturnsEmptyArrayIfNoTriggersAreDefined", func(t *testing.T) {
 
@@ -986,6 +1405,53 @@ func TestGetAllTriggers(t *testing.T) {
 		assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-manifest", triggers[2].Release.Name)
 	})
 
+	t.Run("ResolvesHashedCronScheduleForPipelineName", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- cron:
+    schedule: 'H 2 * * *'
+stages:
+  build:
+    image: golang`, true)
+		assert.Nil(t, err)
+
+		// act
+		triggers := manifest.GetAllTriggers("github.com", "ziplineeci", "ziplinee-ci-manifest")
+
+		if assert.Equal(t, 1, len(triggers)) {
+			expected, _ := resolveHashedCronSchedule("H 2 * * *", "github.com/ziplineeci/ziplinee-ci-manifest")
+			assert.Equal(t, expected, triggers[0].Cron.Schedule)
+			assert.Regexp(t, `^([0-9]|[1-5][0-9]) 2 \* \* \*$`, triggers[0].Cron.Schedule)
+		}
+	})
+
+	t.Run("ReplacesGitlabRepositoryWithActualPipelineNameIfValueIsSelf", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+bots:
+  mr-bot:
+    triggers:
+    - gitlab:
+        events:
+        - Merge Request Hook
+    stages:
+      welcome:
+        image: alpine`, true)
+		assert.Nil(t, err)
+
+		// act
+		triggers := manifest.GetAllTriggers("gitlab.com", "ziplineeci", "ziplinee-ci-manifest")
+
+		if assert.Equal(t, 1, len(triggers)) {
+			assert.Equal(t, "gitlab.com/ziplineeci/ziplinee-ci-manifest", triggers[0].Gitlab.Repository)
+			assert.Equal(t, "mr-bot", triggers[0].BotAction.Bot)
+		}
+	})
+
 	t.Run("DoesNotReplacePipelineNameWithActualPipelineNameIfValueIsNotSelf", func(t *testing.T) {
 
 		manifest := ZiplineeManifest{
@@ -1033,6 +1499,196 @@ func TestGetAllTriggers(t *testing.T) {
 	})
 }
 
+func TestMatchingTriggers(t *testing.T) {
+	t.Run("ReturnsBuildAndReleaseTriggersThatFireForEvent", func(t *testing.T) {
+
+		manifest, err := ReadManifestFromFile(GetDefaultManifestPreferences(), "test-manifest-with-triggers.yaml", true)
+		assert.Nil(t, err)
+
+		event := ZiplineeEvent{
+			PubSub: &ZiplineePubSubEvent{
+				Project: "my-project-id",
+				Topic:   "topic-name",
+			},
+		}
+
+		// act
+		matches := manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", &event)
+
+		if assert.Equal(t, 2, len(matches)) {
+			assert.Equal(t, TriggerTypeBuild, matches[0].Type)
+			assert.Equal(t, "master", matches[0].BuildAction.Branch)
+			assert.Nil(t, matches[0].ReleaseAction)
+			assert.Equal(t, TriggerTypeRelease, matches[1].Type)
+			assert.Equal(t, "development", matches[1].ReleaseAction.Target)
+			assert.Nil(t, matches[1].BuildAction)
+		}
+	})
+
+	t.Run("ReturnsTriggersWithSelfReplacedByPipelineName", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - release:
+        name: self
+        target: staging
+bots:
+  any-bot:
+    triggers:
+    - github:
+        events:
+        - pull_request
+    stages:
+      respond:
+        image: alpine`, true)
+		assert.Nil(t, err)
+
+		event := ZiplineeEvent{
+			Release: &ZiplineeReleaseEvent{
+				RepoSource: "github.com",
+				RepoOwner:  "ziplineeci",
+				RepoName:   "ziplinee-ci-api",
+				Target:     "staging",
+				Status:     "succeeded",
+				Event:      "finished",
+			},
+		}
+
+		// act
+		matches := manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", &event)
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, TriggerTypeRelease, matches[0].Type)
+			assert.Equal(t, "production", matches[0].ReleaseAction.Target)
+			assert.Equal(t, "same", matches[0].ReleaseAction.Version)
+		}
+	})
+
+	t.Run("ReturnsTriggersWithResolvedConcurrency", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+triggers:
+- git:
+    repository: github.com/ziplineeci/ziplinee-ci-api
+  concurrency:
+    cancelInProgress: true
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    concurrency:
+      debounce: 1m
+    triggers:
+    - pipeline:
+        name: self
+    stages:
+      deploy:
+        image: alpine`, true)
+		assert.Nil(t, err)
+
+		event := ZiplineeEvent{
+			Git: &ZiplineeGitEvent{
+				Event:      "push",
+				Repository: "github.com/ziplineeci/ziplinee-ci-api",
+				Branch:     "master",
+			},
+		}
+
+		// act
+		matches := manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", &event)
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, &ZiplineeResolvedConcurrency{
+				Group:            "github.com/ziplineeci/ziplinee-ci-api/build",
+				MaxInFlight:      1,
+				CancelInProgress: true,
+			}, matches[0].Concurrency)
+		}
+
+		event = ZiplineeEvent{
+			Pipeline: &ZiplineePipelineEvent{
+				RepoSource: "github.com",
+				RepoOwner:  "ziplineeci",
+				RepoName:   "ziplinee-ci-api",
+				Branch:     "master",
+				Status:     "succeeded",
+				Event:      "finished",
+			},
+		}
+
+		// act
+		matches = manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", &event)
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, &ZiplineeResolvedConcurrency{
+				Group:       "github.com/ziplineeci/ziplinee-ci-api/release/production",
+				MaxInFlight: 1,
+				Debounce:    time.Minute,
+			}, matches[0].Concurrency)
+		}
+	})
+
+	t.Run("ReturnsBotTriggersThatFireForEvent", func(t *testing.T) {
+
+		manifest, err := ReadManifestFromFile(GetDefaultManifestPreferences(), "test-manifest-with-bots.yaml", true)
+		assert.Nil(t, err)
+
+		event := ZiplineeEvent{
+			Bitbucket: &ZiplineeBitbucketEvent{
+				Event:      "repo:fork",
+				Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api",
+			},
+		}
+
+		// act
+		matches := manifest.MatchingTriggers("bitbucket.org", "ziplineeci", "ziplinee-ci-api", &event)
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, TriggerTypeBot, matches[0].Type)
+			assert.Equal(t, "any-bot", matches[0].BotAction.Bot)
+		}
+	})
+
+	t.Run("ReturnsAllTriggersThatFireForLastEventInStream", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - all:
+        triggers:
+        - pipeline:
+            name: github.com/ziplineeci/ziplinee-ci-contracts
+        - pipeline:
+            name: github.com/ziplineeci/ziplinee-ci-foundation
+    stages:
+      deploy:
+        image: alpine`, true)
+		assert.Nil(t, err)
+
+		contracts := &ZiplineeEvent{Pipeline: &ZiplineePipelineEvent{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-contracts", Branch: "master", Event: "finished", Status: "succeeded"}}
+		foundation := &ZiplineeEvent{Pipeline: &ZiplineePipelineEvent{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-foundation", Branch: "master", Event: "finished", Status: "succeeded"}}
+
+		// act
+		matches := manifest.MatchingTriggersForEvents("github.com", "ziplineeci", "ziplinee-ci-api", []*ZiplineeEvent{contracts, foundation})
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, TriggerTypeRelease, matches[0].Type)
+			assert.Equal(t, "production", matches[0].ReleaseAction.Target)
+		}
+		assert.Equal(t, 0, len(manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", foundation)))
+	})
+}
+
 func TestValidate(t *testing.T) {
 	t.Run("ReturnsErrorIfTrackIsNotDevBetaOrStable", func(t *testing.T) {
 
@@ -1113,6 +1769,138 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestValidateAll(t *testing.T) {
+	t.Run("ReturnsAllErrorsAcrossTheManifest", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+builder:
+  track: nightly
+stages:
+  build:
+    commands:
+    - go build
+  parallel:
+    parallelStages:
+      test:
+        commands:
+        - go test
+triggers:
+- pipeline:
+    event: unknown
+    name: self
+releases:
+  staging:
+    stages:
+      deploy:
+        commands:
+        - kubectl apply
+bots:
+  any-bot:
+    stages:
+      respond:
+        commands:
+        - echo hi`, false)
+		assert.Nil(t, err)
+
+		// act
+		result := manifest.ValidateAll(*GetDefaultManifestPreferences())
+
+		if assert.Equal(t, 6, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeBuilderInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "builder.track", result.Errors()[0].Path)
+			assert.Equal(t, ValidationCodeStageInvalid, result.Errors()[1].Code)
+			assert.Equal(t, "stages.build", result.Errors()[1].Path)
+			assert.Equal(t, "stages.parallel.parallelStages.test", result.Errors()[2].Path)
+			assert.Equal(t, ValidationCodeTriggerInvalid, result.Errors()[3].Code)
+			assert.Equal(t, "triggers[0].pipeline", result.Errors()[3].Path)
+			assert.Equal(t, "releases.staging.stages.deploy", result.Errors()[4].Path)
+			assert.Equal(t, "bots.any-bot.stages.respond", result.Errors()[5].Path)
+		}
+	})
+
+	t.Run("ReturnsWarningForDeprecatedReadinessProperties", func(t *testing.T) {
+
+		manifest, err := ReadManifestFromFile(GetDefaultManifestPreferences(), "test-manifest.yaml", false)
+		assert.Nil(t, err)
+
+		// act
+		result := manifest.ValidateAll(*GetDefaultManifestPreferences())
+
+		assert.False(t, result.HasErrors())
+		if assert.Equal(t, 1, len(result.Warnings())) {
+			assert.Equal(t, ValidationCodeReadinessDeprecated, result.Warnings()[0].Code)
+			assert.Equal(t, "stages.test-alpha-version.services[0].readiness", result.Warnings()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForInvalidWhenExpressions", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+    when: status == 'succeeded' &&
+    services:
+    - name: database
+      image: cockroachdb/cockroach
+      when: (status == 'succeeded'`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 2, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeWhenInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "stages.build.when", result.Errors()[0].Path)
+			assert.Equal(t, 5, result.Errors()[0].Line)
+			assert.Equal(t, ValidationCodeWhenInvalid, result.Errors()[1].Code)
+			assert.Equal(t, "stages.build.services[0].when", result.Errors()[1].Path)
+			assert.Equal(t, 9, result.Errors()[1].Line)
+		}
+	})
+
+	t.Run("ReturnsWarningForUnknownVariablesInWhenExpressions", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+    when: stauts == 'succeeded'
+    services:
+    - name: database
+      image: cockroachdb/cockroach
+      when: labels.app == 'api' && env.VAR_A == 'a'`, false)
+		assert.Nil(t, err)
+
+		// act
+		result := manifest.ValidateAll(*GetDefaultManifestPreferences())
+
+		assert.False(t, result.HasErrors())
+		if assert.Equal(t, 1, len(result.Warnings())) {
+			assert.Equal(t, ValidationCodeWhenUnknownVariable, result.Warnings()[0].Code)
+			assert.Equal(t, "stages.build.when", result.Warnings()[0].Path)
+			assert.Contains(t, result.Warnings()[0].Error(), "stauts")
+		}
+	})
+
+	t.Run("ReturnsLocatedErrorsFromReadManifest", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    commands:
+    - go build
+  bake:
+    commands:
+    - docker build .`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 2, len(result.Errors())) {
+			assert.Equal(t, 3, result.Errors()[0].Line)
+			assert.Equal(t, 6, result.Errors()[1].Line)
+		}
+	})
+}
+
 func TestDeepCopy(t *testing.T) {
 
 	t.Run("ReturnsCopyOfManifestAndAllStages", func(t *testing.T) {
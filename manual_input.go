@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	foundation "github.com/ziplineeci/ziplinee-foundation"
+)
+
+type ManualInputType string
+
+const (
+	ManualInputTypeUnknown ManualInputType = ""
+	ManualInputTypeString  ManualInputType = "string"
+	ManualInputTypeBool    ManualInputType = "bool"
+	ManualInputTypeChoice  ManualInputType = "choice"
+	ManualInputTypeNumber  ManualInputType = "number"
+)
+
+var (
+	manualInputNameRegex   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+	manualInputEnvVarRegex = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// ZiplineeManualInput declares a parameter that can be set when manually starting a release or bot run
+type ZiplineeManualInput struct {
+	Name        string          `yaml:"name,omitempty" json:"name,omitempty"`
+	Type        ManualInputType `yaml:"type,omitempty" json:"type,omitempty"`
+	Description string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Default     string          `yaml:"default,omitempty" json:"default,omitempty"`
+	Values      []string        `yaml:"values,omitempty" json:"values,omitempty"`
+	Required    bool            `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// SetDefaults sets defaults for ZiplineeManualInput
+func (i *ZiplineeManualInput) SetDefaults() {
+	if i.Type == ManualInputTypeUnknown {
+		i.Type = ManualInputTypeString
+	}
+}
+
+// Validate checks if ZiplineeManualInput is valid
+func (i *ZiplineeManualInput) Validate() (err error) {
+	if i.Name == "" {
+		return fmt.Errorf("Set name for inputs, it's used as parameter name and to pass the value to stages as environment variable")
+	}
+	if !manualInputNameRegex.MatchString(i.Name) {
+		return fmt.Errorf("Invalid input name %v, start with a letter and only use letters, digits, dashes and underscores", i.Name)
+	}
+
+	switch i.Type {
+	case ManualInputTypeString, ManualInputTypeNumber:
+	case ManualInputTypeBool:
+		if len(i.Values) > 0 {
+			return fmt.Errorf("Do not set values for input %v of type bool", i.Name)
+		}
+	case ManualInputTypeChoice:
+		if len(i.Values) == 0 {
+			return fmt.Errorf("Set values for input %v of type choice to the values that can be chosen from", i.Name)
+		}
+	default:
+		return fmt.Errorf("Set type for input %v to 'string', 'bool', 'choice' or 'number'", i.Name)
+	}
+
+	if i.Type == ManualInputTypeNumber {
+		for _, v := range i.Values {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("Value %v for input %v of type number is not a number", v, i.Name)
+			}
+		}
+	}
+
+	if i.Default != "" {
+		if _, err := i.ValidateValue(i.Default); err != nil {
+			return fmt.Errorf("Invalid default for input %v: %v", i.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateValue checks whether a value is valid for the input's type and allowed values, and returns it in normalized form
+func (i *ZiplineeManualInput) ValidateValue(value string) (normalized string, err error) {
+
+	normalized = value
+
+	switch i.Type {
+	case ManualInputTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("Value %v is not a bool", value)
+		}
+		normalized = strconv.FormatBool(b)
+	case ManualInputTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("Value %v is not a number", value)
+		}
+	}
+
+	if len(i.Values) > 0 && !foundation.StringArrayContains(i.Values, normalized) {
+		return "", fmt.Errorf("Value %v is not one of the allowed values %v", value, strings.Join(i.Values, ", "))
+	}
+
+	return normalized, nil
+}
+
+// validateManualInputs checks all inputs and whether their names are unique
+func validateManualInputs(inputs []*ZiplineeManualInput) (err error) {
+	names := map[string]bool{}
+	for i, input := range inputs {
+		if input == nil {
+			continue
+		}
+		err = input.Validate()
+		if err == nil && names[input.Name] {
+			err = fmt.Errorf("Input %v is declared more than once", input.Name)
+		}
+		if err != nil {
+			return wrapManifestError(fmt.Sprintf("inputs[%v]", i), err)
+		}
+		names[input.Name] = true
+	}
+
+	return nil
+}
+
+// ResolveParameters validates the parameters in the event against the declared inputs and returns the values for all inputs,
+// using defaults for parameters that aren't set
+func (e *ZiplineeManualEvent) ResolveParameters(inputs []*ZiplineeManualInput) (values map[string]string, err error) {
+
+	values = map[string]string{}
+	declared := map[string]bool{}
+
+	for _, input := range inputs {
+		if input == nil {
+			continue
+		}
+		declared[input.Name] = true
+
+		value, ok := e.Parameters[input.Name]
+		if !ok || value == "" {
+			if input.Required && input.Default == "" {
+				return nil, fmt.Errorf("Parameter %v is required", input.Name)
+			}
+			value = input.Default
+		}
+		if value == "" {
+			continue
+		}
+
+		values[input.Name], err = input.ValidateValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid parameter %v: %v", input.Name, err)
+		}
+	}
+
+	// loop parameters in a fixed order so the error for undeclared parameters is stable
+	names := make([]string, 0, len(e.Parameters))
+	for name := range e.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !declared[name] {
+			return nil, fmt.Errorf("Parameter %v is not declared as input", name)
+		}
+	}
+
+	return values, nil
+}
+
+// ManualParameterEnvVars returns the environment variables to pass resolved parameter values to stages, i.e. ZIPLINEE_INPUT_TARGET_ENVIRONMENT for input target-environment
+func ManualParameterEnvVars(values map[string]string) (envvars map[string]string) {
+	envvars = map[string]string{}
+	for name, value := range values {
+		envvars["ZIPLINEE_INPUT_"+manualInputEnvVarRegex.ReplaceAllString(strings.ToUpper(name), "_")] = value
+	}
+
+	return
+}
@@ -0,0 +1,181 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZiplineeManualInputSetDefaults(t *testing.T) {
+	t.Run("SetsTypeToStringIfEmpty", func(t *testing.T) {
+
+		input := ZiplineeManualInput{
+			Name: "message",
+		}
+
+		// act
+		input.SetDefaults()
+
+		assert.Equal(t, ManualInputTypeString, input.Type)
+	})
+}
+
+func TestZiplineeManualInputValidate(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		input ZiplineeManualInput
+		valid bool
+	}{
+		{"ValidString", ZiplineeManualInput{Name: "message", Type: ManualInputTypeString}, true},
+		{"EmptyName", ZiplineeManualInput{Type: ManualInputTypeString}, false},
+		{"NameWithSpaces", ZiplineeManualInput{Name: "target environment", Type: ManualInputTypeString}, false},
+		{"UnknownType", ZiplineeManualInput{Name: "message", Type: "text"}, false},
+		{"ChoiceWithValues", ZiplineeManualInput{Name: "environment", Type: ManualInputTypeChoice, Values: []string{"staging", "production"}, Default: "staging"}, true},
+		{"ChoiceWithoutValues", ZiplineeManualInput{Name: "environment", Type: ManualInputTypeChoice}, false},
+		{"ChoiceWithDefaultNotInValues", ZiplineeManualInput{Name: "environment", Type: ManualInputTypeChoice, Values: []string{"staging", "production"}, Default: "development"}, false},
+		{"BoolWithDefault", ZiplineeManualInput{Name: "dry-run", Type: ManualInputTypeBool, Default: "true"}, true},
+		{"BoolWithInvalidDefault", ZiplineeManualInput{Name: "dry-run", Type: ManualInputTypeBool, Default: "maybe"}, false},
+		{"BoolWithValues", ZiplineeManualInput{Name: "dry-run", Type: ManualInputTypeBool, Values: []string{"true"}}, false},
+		{"NumberWithDefault", ZiplineeManualInput{Name: "replicas", Type: ManualInputTypeNumber, Default: "3"}, true},
+		{"NumberWithNonNumericValues", ZiplineeManualInput{Name: "replicas", Type: ManualInputTypeNumber, Values: []string{"1", "many"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// act
+			err := tc.input.Validate()
+
+			assert.Equal(t, tc.valid, err == nil, "error: %v", err)
+		})
+	}
+}
+
+func TestValidateManualInputs(t *testing.T) {
+	t.Run("ReturnsErrorIfInputNameIsDeclaredTwice", func(t *testing.T) {
+
+		inputs := []*ZiplineeManualInput{
+			{Name: "message", Type: ManualInputTypeString},
+			{Name: "message", Type: ManualInputTypeString},
+		}
+
+		// act
+		err := validateManualInputs(inputs)
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "inputs[1]", err.(*ManifestError).Path)
+		}
+	})
+}
+
+func TestZiplineeManualEventResolveParameters(t *testing.T) {
+
+	inputs := []*ZiplineeManualInput{
+		{Name: "environment", Type: ManualInputTypeChoice, Values: []string{"staging", "production"}, Required: true},
+		{Name: "dry-run", Type: ManualInputTypeBool, Default: "false"},
+		{Name: "replicas", Type: ManualInputTypeNumber},
+		{Name: "message", Type: ManualInputTypeString},
+	}
+
+	t.Run("ReturnsParameterValuesAndDefaults", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			UserID: "user@server.com",
+			Parameters: map[string]string{
+				"environment": "production",
+				"replicas":    "3",
+			},
+		}
+
+		// act
+		values, err := event.ResolveParameters(inputs)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"environment": "production", "dry-run": "false", "replicas": "3"}, values)
+	})
+
+	t.Run("NormalizesBoolValues", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			Parameters: map[string]string{
+				"environment": "staging",
+				"dry-run":     "1",
+			},
+		}
+
+		// act
+		values, err := event.ResolveParameters(inputs)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "true", values["dry-run"])
+	})
+
+	t.Run("ReturnsErrorIfRequiredParameterIsMissing", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			Parameters: map[string]string{
+				"replicas": "3",
+			},
+		}
+
+		// act
+		_, err := event.ResolveParameters(inputs)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfValueIsNotAllowed", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			Parameters: map[string]string{
+				"environment": "development",
+			},
+		}
+
+		// act
+		_, err := event.ResolveParameters(inputs)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfValueHasWrongType", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			Parameters: map[string]string{
+				"environment": "staging",
+				"replicas":    "three",
+			},
+		}
+
+		// act
+		_, err := event.ResolveParameters(inputs)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfParameterIsNotDeclared", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			Parameters: map[string]string{
+				"environment": "staging",
+				"force":       "true",
+			},
+		}
+
+		// act
+		_, err := event.ResolveParameters(inputs)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestManualParameterEnvVars(t *testing.T) {
+	t.Run("ReturnsUppercasedPrefixedEnvVarNames", func(t *testing.T) {
+
+		// act
+		envvars := ManualParameterEnvVars(map[string]string{"target-environment": "production", "dry_run": "true"})
+
+		assert.Equal(t, map[string]string{"ZIPLINEE_INPUT_TARGET_ENVIRONMENT": "production", "ZIPLINEE_INPUT_DRY_RUN": "true"}, envvars)
+	})
+}
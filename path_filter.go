@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// changedFilesMatch indicates whether at least one changed file matches any of the paths globs and none of the pathsIgnore globs;
+// without any globs there's nothing to filter on and it always matches
+func changedFilesMatch(paths, pathsIgnore, changedFiles []string) bool {
+	if len(paths) == 0 && len(pathsIgnore) == 0 {
+		return true
+	}
+
+	for _, file := range changedFiles {
+		if len(paths) > 0 && !globsMatch(paths, file) {
+			continue
+		}
+		if globsMatch(pathsIgnore, file) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// globsMatch indicates whether any of the globs matches the file path
+func globsMatch(globs []string, file string) bool {
+	for _, glob := range globs {
+		if match, err := globMatch(glob, file); err == nil && match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globMatch matches a file path against a glob where * and ? do not cross directories and ** matches any number of directories
+func globMatch(glob, file string) (bool, error) {
+	return compiledGlobs.MatchString(glob, strings.TrimPrefix(file, "/"))
+}
+
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(strings.TrimSpace(glob), "/")
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// **/ matches zero or more directories
+					i++
+					pattern.WriteString("(.*/)?")
+				} else {
+					pattern.WriteString(".*")
+				}
+			} else {
+				pattern.WriteString("[^/]*")
+			}
+		case '?':
+			pattern.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("Glob %v has an unterminated character class", glob)
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			pattern.WriteString("[" + class + "]")
+			i += end
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	pattern.WriteString("$")
+
+	return regexp.Compile(pattern.String())
+}
+
+// validatePathFilters checks whether all paths and pathsIgnore globs are valid
+func validatePathFilters(triggerType string, paths, pathsIgnore []string) (err error) {
+	for i, glob := range paths {
+		if _, err := compiledGlobs.Get(glob); err != nil {
+			return wrapManifestError(fmt.Sprintf("paths[%v]", i), fmt.Errorf("Invalid %v.paths entry %v in your trigger: %v", triggerType, glob, err))
+		}
+	}
+	for i, glob := range pathsIgnore {
+		if _, err := compiledGlobs.Get(glob); err != nil {
+			return wrapManifestError(fmt.Sprintf("pathsIgnore[%v]", i), fmt.Errorf("Invalid %v.pathsIgnore entry %v in your trigger: %v", triggerType, glob, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+
+	testCases := []struct {
+		glob  string
+		file  string
+		match bool
+	}{
+		{"services/api/*.go", "services/api/main.go", true},
+		{"services/api/*.go", "services/api/handlers/main.go", false},
+		{"services/api/**", "services/api/handlers/main.go", true},
+		{"services/**/*.go", "services/main.go", true},
+		{"services/**/*.go", "services/api/handlers/main.go", true},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/setup/README.md", true},
+		{"/docs/*", "docs/index.md", true},
+		{"docs/?.md", "docs/a.md", true},
+		{"docs/?.md", "docs/ab.md", false},
+		{"docs/[ab].md", "docs/b.md", true},
+		{"docs/[!ab].md", "docs/b.md", false},
+		{"go.mod", "go.sum", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.glob+"_"+tc.file, func(t *testing.T) {
+
+			// act
+			match, err := globMatch(tc.glob, tc.file)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+
+	t.Run("ReturnsErrorForUnterminatedCharacterClass", func(t *testing.T) {
+
+		// act
+		_, err := globMatch("docs/[ab.md", "docs/a.md")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestChangedFilesMatch(t *testing.T) {
+	t.Run("ReturnsTrueIfNoGlobsAreSet", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch(nil, nil, nil)
+
+		assert.True(t, match)
+	})
+
+	t.Run("ReturnsTrueIfAnyChangedFileMatchesPaths", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch([]string{"services/api/**"}, nil, []string{"services/web/main.go", "services/api/main.go"})
+
+		assert.True(t, match)
+	})
+
+	t.Run("ReturnsFalseIfNoChangedFileMatchesPaths", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch([]string{"services/api/**"}, nil, []string{"services/web/main.go"})
+
+		assert.False(t, match)
+	})
+
+	t.Run("ReturnsFalseIfAllChangedFilesAreIgnored", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch(nil, []string{"**/*.md", "docs/**"}, []string{"README.md", "docs/setup.png"})
+
+		assert.False(t, match)
+	})
+
+	t.Run("ReturnsTrueIfAnyChangedFileIsNotIgnored", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch(nil, []string{"**/*.md"}, []string{"README.md", "main.go"})
+
+		assert.True(t, match)
+	})
+
+	t.Run("ReturnsFalseIfFileMatchingPathsIsIgnored", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch([]string{"services/api/**"}, []string{"**/*_test.go"}, []string{"services/api/main_test.go"})
+
+		assert.False(t, match)
+	})
+
+	t.Run("ReturnsFalseIfGlobsAreSetButThereAreNoChangedFiles", func(t *testing.T) {
+
+		// act
+		match := changedFilesMatch([]string{"services/api/**"}, nil, []string{})
+
+		assert.False(t, match)
+	})
+}
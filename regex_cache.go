@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"regexp"
+	"sync"
+)
+
+// regexCacheMaxSize limits the number of compiled patterns kept per cache; patterns come from manifests, so a long running
+// server sees new ones over time and the cache is reset when it's full rather than growing without bounds
+const regexCacheMaxSize = 10000
+
+var (
+	compiledRegexes = newRegexCache(regexCacheMaxSize, regexp.Compile)
+	compiledGlobs   = newRegexCache(regexCacheMaxSize, globToRegexp)
+)
+
+// regexCache holds compiled regexes by pattern and is safe for concurrent use, so patterns evaluated for every event only get compiled once
+type regexCache struct {
+	mutex   sync.RWMutex
+	entries map[string]regexCacheEntry
+	maxSize int
+	compile func(string) (*regexp.Regexp, error)
+}
+
+type regexCacheEntry struct {
+	regex *regexp.Regexp
+	err   error
+}
+
+func newRegexCache(maxSize int, compile func(string) (*regexp.Regexp, error)) *regexCache {
+	return &regexCache{
+		entries: map[string]regexCacheEntry{},
+		maxSize: maxSize,
+		compile: compile,
+	}
+}
+
+// Get returns the compiled regex for the pattern, compiling it if it isn't cached yet; compile errors are cached as well
+func (c *regexCache) Get(pattern string) (*regexp.Regexp, error) {
+
+	c.mutex.RLock()
+	entry, found := c.entries[pattern]
+	c.mutex.RUnlock()
+
+	if found {
+		return entry.regex, entry.err
+	}
+
+	entry.regex, entry.err = c.compile(pattern)
+	if c.maxSize <= 0 {
+		return entry.regex, entry.err
+	}
+
+	c.mutex.Lock()
+	if len(c.entries) >= c.maxSize {
+		c.entries = map[string]regexCacheEntry{}
+	}
+	c.entries[pattern] = entry
+	c.mutex.Unlock()
+
+	return entry.regex, entry.err
+}
+
+// Len returns the number of cached patterns
+func (c *regexCache) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return len(c.entries)
+}
+
+// MatchString reports whether the value matches the pattern, like regexp.MatchString but with the compiled pattern cached
+func (c *regexCache) MatchString(pattern, value string) (bool, error) {
+	regex, err := c.Get(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return regex.MatchString(value), nil
+}
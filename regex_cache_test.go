@@ -0,0 +1,149 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexCache(t *testing.T) {
+	t.Run("ReturnsSameCompiledRegexForSamePattern", func(t *testing.T) {
+
+		cache := newRegexCache(10, regexp.Compile)
+
+		// act
+		first, err := cache.Get("^(main|master)$")
+		second, _ := cache.Get("^(main|master)$")
+
+		assert.Nil(t, err)
+		assert.True(t, first == second)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("CachesCompileErrors", func(t *testing.T) {
+
+		cache := newRegexCache(10, regexp.Compile)
+
+		// act
+		_, err := cache.Get("^(main$")
+		_, secondErr := cache.Get("^(main$")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, err, secondErr)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("ResetsWhenMaxSizeIsReached", func(t *testing.T) {
+
+		cache := newRegexCache(2, regexp.Compile)
+		cache.Get("a")
+		cache.Get("b")
+
+		// act
+		cache.Get("c")
+
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("DoesNotCacheIfMaxSizeIsZero", func(t *testing.T) {
+
+		cache := newRegexCache(0, regexp.Compile)
+
+		// act
+		match, err := cache.MatchString("^(main|master)$", "main")
+
+		assert.Nil(t, err)
+		assert.True(t, match)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("IsSafeForConcurrentUse", func(t *testing.T) {
+
+		cache := newRegexCache(50, regexp.Compile)
+
+		// act
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					pattern := fmt.Sprintf("^(branch-%v)$", (i*j)%75)
+					match, err := cache.MatchString(pattern, fmt.Sprintf("branch-%v", (i*j)%75))
+					assert.Nil(t, err)
+					assert.True(t, match)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.True(t, cache.Len() <= 50)
+	})
+}
+
+// benchmarkTriggers returns a large set of triggers of the types the server evaluates for every incoming event
+func benchmarkTriggers(n int) []*ZiplineeTrigger {
+	triggers := make([]*ZiplineeTrigger, 0, n)
+	for i := 0; i < n; i++ {
+		var trigger *ZiplineeTrigger
+		switch i % 3 {
+		case 0:
+			trigger = &ZiplineeTrigger{Pipeline: &ZiplineePipelineTrigger{Name: fmt.Sprintf("github.com/ziplineeci/pipeline-%v", i%100), Branch: "master|main|release-.+"}}
+		case 1:
+			trigger = &ZiplineeTrigger{Git: &ZiplineeGitTrigger{Repository: fmt.Sprintf("github.com/ziplineeci/repo-%v", i%100), Branch: "!~feature/.+"}}
+		default:
+			trigger = &ZiplineeTrigger{PubSub: &ZiplineePubSubTrigger{Project: fmt.Sprintf("project-%v", i%10), Topic: "image-(pushed|deleted)"}}
+		}
+		trigger.SetDefaults(*GetDefaultManifestPreferences(), TriggerTypeBuild, "")
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers
+}
+
+func benchmarkTriggersFire(b *testing.B, cache *regexCache) {
+	original := compiledRegexes
+	compiledRegexes = cache
+	defer func() { compiledRegexes = original }()
+
+	triggers := benchmarkTriggers(3000)
+	events := []*ZiplineeEvent{
+		{Pipeline: &ZiplineePipelineEvent{Event: "finished", Status: "succeeded", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "pipeline-3", Branch: "main"}},
+		{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/repo-4", Branch: "main"}},
+		{PubSub: &ZiplineePubSubEvent{Project: "project-5", Topic: "image-pushed"}},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, t := range triggers {
+			t.Fires(events[i%len(events)])
+		}
+	}
+}
+
+func BenchmarkTriggersFire(b *testing.B) {
+	b.Run("WithRegexCache", func(b *testing.B) {
+		benchmarkTriggersFire(b, newRegexCache(regexCacheMaxSize, regexp.Compile))
+	})
+
+	b.Run("WithoutRegexCache", func(b *testing.B) {
+		benchmarkTriggersFire(b, newRegexCache(0, regexp.Compile))
+	})
+}
+
+func BenchmarkRegexMatch(b *testing.B) {
+	b.Run("WithRegexCache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			regexMatch("master|main|release-.+", "release-1.2")
+		}
+	})
+
+	b.Run("WithoutRegexCache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			regexp.MatchString("^(master|main|release-.+)$", "release-1.2")
+		}
+	})
+}
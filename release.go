@@ -1,6 +1,8 @@
 package manifest
 
 import (
+	"fmt"
+
 	"github.com/jinzhu/copier"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -12,8 +14,11 @@ type ZiplineeRelease struct {
 	CloneRepository *bool                    `yaml:"clone,omitempty" json:",omitempty"`
 	Actions         []*ZiplineeReleaseAction `yaml:"actions,omitempty" json:",omitempty"`
 	Triggers        []*ZiplineeTrigger       `yaml:"triggers,omitempty" json:",omitempty"`
+	Inputs          []*ZiplineeManualInput   `yaml:"inputs,omitempty" json:",omitempty"`
+	Concurrency     *ZiplineeConcurrency     `yaml:"concurrency,omitempty" json:",omitempty"`
 	Stages          []*ZiplineeStage         `yaml:"-" json:",omitempty"`
 	Template        string                   `yaml:"template,omitempty"`
+	TemplateParams  map[string]string        `yaml:"templateParams,omitempty" json:",omitempty"`
 }
 
 // UnmarshalYAML customizes unmarshalling an ZiplineeRelease
@@ -25,8 +30,11 @@ func (release *ZiplineeRelease) UnmarshalYAML(unmarshal func(interface{}) error)
 		CloneRepository *bool                    `yaml:"clone"`
 		Actions         []*ZiplineeReleaseAction `yaml:"actions"`
 		Triggers        []*ZiplineeTrigger       `yaml:"triggers"`
+		Inputs          []*ZiplineeManualInput   `yaml:"inputs"`
+		Concurrency     *ZiplineeConcurrency     `yaml:"concurrency"`
 		Stages          yaml.MapSlice            `yaml:"stages"`
 		Template        string                   `yaml:"template"`
+		TemplateParams  map[string]string        `yaml:"templateParams"`
 	}
 
 	// unmarshal to auxiliary type
@@ -40,7 +48,10 @@ func (release *ZiplineeRelease) UnmarshalYAML(unmarshal func(interface{}) error)
 	release.CloneRepository = aux.CloneRepository
 	release.Actions = aux.Actions
 	release.Triggers = aux.Triggers
+	release.Inputs = aux.Inputs
+	release.Concurrency = aux.Concurrency
 	release.Template = aux.Template
+	release.TemplateParams = aux.TemplateParams
 
 	for _, mi := range aux.Stages {
 
@@ -51,7 +62,7 @@ func (release *ZiplineeRelease) UnmarshalYAML(unmarshal func(interface{}) error)
 
 		var stage *ZiplineeStage
 		if err := yaml.Unmarshal(bytes, &stage); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("stages.%v", mi.Key), err)
 		}
 		if stage == nil {
 			stage = &ZiplineeStage{}
@@ -75,8 +86,11 @@ func (release ZiplineeRelease) MarshalYAML() (out interface{}, err error) {
 		CloneRepository *bool                    `yaml:"clone,omitempty"`
 		Actions         []*ZiplineeReleaseAction `yaml:"actions,omitempty"`
 		Triggers        []*ZiplineeTrigger       `yaml:"triggers,omitempty"`
+		Inputs          []*ZiplineeManualInput   `yaml:"inputs,omitempty"`
+		Concurrency     *ZiplineeConcurrency     `yaml:"concurrency,omitempty"`
 		Stages          yaml.MapSlice            `yaml:"stages,omitempty"`
 		Template        string                   `yaml:"template,omitempty"`
+		TemplateParams  map[string]string        `yaml:"templateParams,omitempty"`
 	}
 
 	// map auxiliary properties
@@ -84,7 +98,10 @@ func (release ZiplineeRelease) MarshalYAML() (out interface{}, err error) {
 	aux.CloneRepository = release.CloneRepository
 	aux.Actions = release.Actions
 	aux.Triggers = release.Triggers
+	aux.Inputs = release.Inputs
+	aux.Concurrency = release.Concurrency
 	aux.Template = release.Template
+	aux.TemplateParams = release.TemplateParams
 
 	for _, stage := range release.Stages {
 		aux.Stages = append(aux.Stages, yaml.MapItem{
@@ -110,10 +127,8 @@ func (release *ZiplineeRelease) InitFromTemplate(releaseTemplates map[string]*Zi
 	if release.Template != "" {
 		// check if template with defined name exists, and use its values overridden by this releases values
 
-		if releaseTemplate, found := releaseTemplates[release.Template]; found && releaseTemplate != nil {
-
-			// deep copy so there's no pointers shared with other releases
-			template := releaseTemplate.DeepCopy()
+		// resolving returns a deep copy, so there's no pointers shared with other releases
+		if template := resolveReleaseTemplate(release.Template, releaseTemplates); template != nil {
 
 			if release.Builder != nil {
 				template.Builder = release.Builder
@@ -139,11 +154,26 @@ func (release *ZiplineeRelease) InitFromTemplate(releaseTemplates map[string]*Zi
 				release.Triggers = template.Triggers
 			}
 
-			if release.Stages != nil && len(release.Stages) > 0 {
-				template.Stages = release.Stages
+			if release.Inputs != nil && len(release.Inputs) > 0 {
+				template.Inputs = release.Inputs
 			} else {
-				release.Stages = template.Stages
+				release.Inputs = template.Inputs
 			}
+
+			if release.Concurrency != nil {
+				template.Concurrency = release.Concurrency
+			} else {
+				release.Concurrency = template.Concurrency
+			}
+
+			// only the template's stages have placeholders for the template parameters
+			values := template.templateParameterValues(release.TemplateParams)
+			for _, s := range template.Stages {
+				s.replaceTemplateParameters(values)
+			}
+
+			// stages of the release override, remove or add to the template's stages by name
+			release.Stages = mergeStages(template.Stages, release.Stages)
 		}
 	}
 }
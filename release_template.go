@@ -1,30 +1,41 @@
 package manifest
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jinzhu/copier"
 	yaml "gopkg.in/yaml.v2"
 )
 
 // ZiplineeReleaseTemplate represents a template for a release target
 type ZiplineeReleaseTemplate struct {
-	Name            string                   `yaml:"-"`
-	Builder         *ZiplineeBuilder         `yaml:"builder,omitempty"`
-	CloneRepository *bool                    `yaml:"clone,omitempty" json:",omitempty"`
-	Actions         []*ZiplineeReleaseAction `yaml:"actions,omitempty" json:",omitempty"`
-	Triggers        []*ZiplineeTrigger       `yaml:"triggers,omitempty" json:",omitempty"`
-	Stages          []*ZiplineeStage         `yaml:"-"`
+	Name            string                       `yaml:"-"`
+	Extends         string                       `yaml:"extends,omitempty" json:",omitempty"`
+	Builder         *ZiplineeBuilder             `yaml:"builder,omitempty"`
+	CloneRepository *bool                        `yaml:"clone,omitempty" json:",omitempty"`
+	Actions         []*ZiplineeReleaseAction     `yaml:"actions,omitempty" json:",omitempty"`
+	Triggers        []*ZiplineeTrigger           `yaml:"triggers,omitempty" json:",omitempty"`
+	Inputs          []*ZiplineeManualInput       `yaml:"inputs,omitempty" json:",omitempty"`
+	Concurrency     *ZiplineeConcurrency         `yaml:"concurrency,omitempty" json:",omitempty"`
+	Parameters      []*ZiplineeTemplateParameter `yaml:"parameters,omitempty" json:",omitempty"`
+	Stages          []*ZiplineeStage             `yaml:"-"`
 }
 
 // UnmarshalYAML customizes unmarshalling an ZiplineeRelease
 func (releaseTemplate *ZiplineeReleaseTemplate) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
 
 	var aux struct {
-		Name            string                   `yaml:"name"`
-		Builder         *ZiplineeBuilder         `yaml:"builder"`
-		CloneRepository *bool                    `yaml:"clone"`
-		Actions         []*ZiplineeReleaseAction `yaml:"actions"`
-		Triggers        []*ZiplineeTrigger       `yaml:"triggers"`
-		Stages          yaml.MapSlice            `yaml:"stages"`
+		Name            string                       `yaml:"name"`
+		Extends         string                       `yaml:"extends"`
+		Builder         *ZiplineeBuilder             `yaml:"builder"`
+		CloneRepository *bool                        `yaml:"clone"`
+		Actions         []*ZiplineeReleaseAction     `yaml:"actions"`
+		Triggers        []*ZiplineeTrigger           `yaml:"triggers"`
+		Inputs          []*ZiplineeManualInput       `yaml:"inputs"`
+		Concurrency     *ZiplineeConcurrency         `yaml:"concurrency"`
+		Parameters      []*ZiplineeTemplateParameter `yaml:"parameters"`
+		Stages          yaml.MapSlice                `yaml:"stages"`
 	}
 
 	// unmarshal to auxiliary type
@@ -34,10 +45,14 @@ func (releaseTemplate *ZiplineeReleaseTemplate) UnmarshalYAML(unmarshal func(int
 
 	// map auxiliary properties
 	releaseTemplate.Name = aux.Name
+	releaseTemplate.Extends = aux.Extends
 	releaseTemplate.Builder = aux.Builder
 	releaseTemplate.CloneRepository = aux.CloneRepository
 	releaseTemplate.Actions = aux.Actions
 	releaseTemplate.Triggers = aux.Triggers
+	releaseTemplate.Inputs = aux.Inputs
+	releaseTemplate.Concurrency = aux.Concurrency
+	releaseTemplate.Parameters = aux.Parameters
 
 	for _, mi := range aux.Stages {
 
@@ -48,7 +63,7 @@ func (releaseTemplate *ZiplineeReleaseTemplate) UnmarshalYAML(unmarshal func(int
 
 		var stage *ZiplineeStage
 		if err := yaml.Unmarshal(bytes, &stage); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("stages.%v", mi.Key), err)
 		}
 		if stage == nil {
 			stage = &ZiplineeStage{}
@@ -67,19 +82,27 @@ func (releaseTemplate *ZiplineeReleaseTemplate) UnmarshalYAML(unmarshal func(int
 func (releaseTemplate ZiplineeReleaseTemplate) MarshalYAML() (out interface{}, err error) {
 
 	var aux struct {
-		Name            string                   `yaml:"-"`
-		Builder         *ZiplineeBuilder         `yaml:"builder,omitempty"`
-		CloneRepository *bool                    `yaml:"clone,omitempty"`
-		Actions         []*ZiplineeReleaseAction `yaml:"actions,omitempty"`
-		Triggers        []*ZiplineeTrigger       `yaml:"triggers,omitempty"`
-		Stages          yaml.MapSlice            `yaml:"stages,omitempty"`
+		Name            string                       `yaml:"-"`
+		Extends         string                       `yaml:"extends,omitempty"`
+		Builder         *ZiplineeBuilder             `yaml:"builder,omitempty"`
+		CloneRepository *bool                        `yaml:"clone,omitempty"`
+		Actions         []*ZiplineeReleaseAction     `yaml:"actions,omitempty"`
+		Triggers        []*ZiplineeTrigger           `yaml:"triggers,omitempty"`
+		Inputs          []*ZiplineeManualInput       `yaml:"inputs,omitempty"`
+		Concurrency     *ZiplineeConcurrency         `yaml:"concurrency,omitempty"`
+		Parameters      []*ZiplineeTemplateParameter `yaml:"parameters,omitempty"`
+		Stages          yaml.MapSlice                `yaml:"stages,omitempty"`
 	}
 
 	// map auxiliary properties
+	aux.Extends = releaseTemplate.Extends
 	aux.Builder = releaseTemplate.Builder
 	aux.CloneRepository = releaseTemplate.CloneRepository
 	aux.Actions = releaseTemplate.Actions
 	aux.Triggers = releaseTemplate.Triggers
+	aux.Inputs = releaseTemplate.Inputs
+	aux.Concurrency = releaseTemplate.Concurrency
+	aux.Parameters = releaseTemplate.Parameters
 
 	for _, stage := range releaseTemplate.Stages {
 		aux.Stages = append(aux.Stages, yaml.MapItem{
@@ -98,3 +121,108 @@ func (releaseTemplate ZiplineeReleaseTemplate) DeepCopy() (target ZiplineeReleas
 
 	return
 }
+
+// resolveReleaseTemplate returns a deep copy of the named template with the values of the templates it extends, or nil if there's no
+// template with that name; a chain extending an unknown template or itself is resolved up to there, validation reports it
+func resolveReleaseTemplate(name string, releaseTemplates map[string]*ZiplineeReleaseTemplate) *ZiplineeReleaseTemplate {
+
+	chain := []*ZiplineeReleaseTemplate{}
+	seen := map[string]bool{}
+	for name != "" && !seen[name] {
+		releaseTemplate, found := releaseTemplates[name]
+		if !found || releaseTemplate == nil {
+			break
+		}
+		seen[name] = true
+		chain = append(chain, releaseTemplate)
+		name = releaseTemplate.Extends
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+
+	resolved := chain[len(chain)-1].DeepCopy()
+	for i := len(chain) - 2; i >= 0; i-- {
+		resolved = chain[i].DeepCopy().extend(resolved)
+	}
+
+	return &resolved
+}
+
+// extend returns the template with the values of the parent template for all values it doesn't set itself; parameters are merged by
+// name and stages are merged into the parent's stages the same way stages of releases are
+func (releaseTemplate ZiplineeReleaseTemplate) extend(parent ZiplineeReleaseTemplate) ZiplineeReleaseTemplate {
+
+	if releaseTemplate.Builder == nil {
+		releaseTemplate.Builder = parent.Builder
+	}
+	if releaseTemplate.CloneRepository == nil {
+		releaseTemplate.CloneRepository = parent.CloneRepository
+	}
+	if len(releaseTemplate.Actions) == 0 {
+		releaseTemplate.Actions = parent.Actions
+	}
+	if len(releaseTemplate.Triggers) == 0 {
+		releaseTemplate.Triggers = parent.Triggers
+	}
+	if len(releaseTemplate.Inputs) == 0 {
+		releaseTemplate.Inputs = parent.Inputs
+	}
+	if releaseTemplate.Concurrency == nil {
+		releaseTemplate.Concurrency = parent.Concurrency
+	}
+
+	parameters := []*ZiplineeTemplateParameter{}
+	for _, p := range parent.Parameters {
+		if p != nil && !releaseTemplate.declaresParameter(p.Name) {
+			parameters = append(parameters, p)
+		}
+	}
+	releaseTemplate.Parameters = append(parameters, releaseTemplate.Parameters...)
+
+	releaseTemplate.Stages = mergeStages(parent.Stages, releaseTemplate.Stages)
+
+	return releaseTemplate
+}
+
+func (releaseTemplate *ZiplineeReleaseTemplate) declaresParameter(name string) bool {
+	for _, p := range releaseTemplate.Parameters {
+		if p != nil && p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateExtends checks whether the templates the template extends exist and don't extend the template itself
+func (releaseTemplate *ZiplineeReleaseTemplate) validateExtends(releaseTemplates map[string]*ZiplineeReleaseTemplate) (err error) {
+
+	chain := []string{releaseTemplate.Name}
+	current := releaseTemplate
+	for current.Extends != "" {
+		if current.Extends == releaseTemplate.Name {
+			return wrapManifestError("extends", fmt.Errorf("Template %v extends itself via %v", releaseTemplate.Name, strings.Join(append(chain, current.Extends), " > ")))
+		}
+		for _, name := range chain {
+			if name == current.Extends {
+				// a cycle the template isn't part of is reported for the templates in the cycle
+				return nil
+			}
+		}
+
+		parent, found := releaseTemplates[current.Extends]
+		if !found || parent == nil {
+			if current == releaseTemplate {
+				return wrapManifestError("extends", fmt.Errorf("Template %v extends template %v, which does not exist", releaseTemplate.Name, current.Extends))
+			}
+			// reported for the template extending it
+			return nil
+		}
+
+		chain = append(chain, current.Extends)
+		current = parent
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestResolveReleaseTemplate(t *testing.T) {
+
+	clone := true
+	releaseTemplates := map[string]*ZiplineeReleaseTemplate{
+		"base": {
+			Name:            "base",
+			CloneRepository: &clone,
+			Parameters:      []*ZiplineeTemplateParameter{{Name: "namespace", Required: true}, {Name: "replicas", Default: "2"}},
+			Stages: []*ZiplineeStage{
+				{Name: "deploy", ContainerImage: "extensions/gke:stable"},
+				{Name: "notify", ContainerImage: "extensions/slack-build-status:stable"},
+			},
+		},
+		"kubernetes": {
+			Name:       "kubernetes",
+			Extends:    "base",
+			Builder:    &ZiplineeBuilder{Track: "dev"},
+			Parameters: []*ZiplineeTemplateParameter{{Name: "replicas", Default: "3"}},
+			Stages:     []*ZiplineeStage{{Name: "deploy", ContainerImage: "extensions/gke:dev"}},
+		},
+		"canary": {
+			Name:    "canary",
+			Extends: "kubernetes",
+			Stages:  []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curlimages/curl", Before: "notify"}},
+		},
+		"a": {Name: "a", Extends: "b"},
+		"b": {Name: "b", Extends: "a"},
+	}
+
+	t.Run("ReturnsNilForUnknownTemplate", func(t *testing.T) {
+
+		// act
+		resolved := resolveReleaseTemplate("unknown", releaseTemplates)
+
+		assert.Nil(t, resolved)
+	})
+
+	t.Run("ReturnsValuesOfAllTemplatesInChain", func(t *testing.T) {
+
+		// act
+		resolved := resolveReleaseTemplate("canary", releaseTemplates)
+
+		if assert.NotNil(t, resolved) {
+			assert.Equal(t, "canary", resolved.Name)
+			assert.Equal(t, "dev", resolved.Builder.Track)
+			assert.True(t, *resolved.CloneRepository)
+			assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(resolved.Stages))
+			assert.Equal(t, "extensions/gke:dev", resolved.Stages[0].ContainerImage)
+			if assert.Equal(t, 2, len(resolved.Parameters)) {
+				assert.Equal(t, "namespace", resolved.Parameters[0].Name)
+				assert.Equal(t, "3", resolved.Parameters[1].Default)
+			}
+		}
+	})
+
+	t.Run("DoesNotChangeTemplatesInChain", func(t *testing.T) {
+
+		// act
+		resolveReleaseTemplate("canary", releaseTemplates)
+
+		assert.Equal(t, 1, len(releaseTemplates["kubernetes"].Stages))
+		assert.Equal(t, "extensions/gke:stable", releaseTemplates["base"].Stages[0].ContainerImage)
+	})
+
+	t.Run("StopsAtCycle", func(t *testing.T) {
+
+		// act
+		resolved := resolveReleaseTemplate("a", releaseTemplates)
+
+		assert.NotNil(t, resolved)
+	})
+}
+
+func TestZiplineeReleaseTemplateValidateExtends(t *testing.T) {
+
+	releaseTemplates := map[string]*ZiplineeReleaseTemplate{
+		"base":           {Name: "base"},
+		"kubernetes":     {Name: "kubernetes", Extends: "base"},
+		"broken":         {Name: "broken", Extends: "unknown"},
+		"extends-broken": {Name: "extends-broken", Extends: "broken"},
+		"a":              {Name: "a", Extends: "b"},
+		"b":              {Name: "b", Extends: "a"},
+		"extends-a":      {Name: "extends-a", Extends: "a"},
+	}
+
+	testCases := []struct {
+		name     string
+		template string
+		valid    bool
+	}{
+		{"WithoutExtends", "base", true},
+		{"ExtendingExistingTemplate", "kubernetes", true},
+		{"ExtendingUnknownTemplate", "broken", false},
+		{"ExtendingTemplateExtendingUnknownTemplate", "extends-broken", true},
+		{"InCycle", "a", false},
+		{"ExtendingTemplateInCycle", "extends-a", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// act
+			err := releaseTemplates[tc.template].validateExtends(releaseTemplates)
+
+			assert.Equal(t, tc.valid, err == nil, "error: %v", err)
+		})
+	}
+}
+
+func TestReadManifestWithExtendedReleaseTemplates(t *testing.T) {
+
+	templates := `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  base:
+    parameters:
+    - name: namespace
+      required: true
+    stages:
+      deploy:
+        image: extensions/gke:stable
+        namespace: '{{ params.namespace }}'
+  kubernetes:
+    extends: base
+    stages:
+      notify:
+        image: extensions/slack-build-status:stable
+`
+
+	t.Run("UsesValuesOfExtendedTemplatesForReleases", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), templates+`
+releases:
+  production:
+    template: kubernetes
+    templateParams:
+      namespace: production`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) {
+			assert.Equal(t, []string{"deploy", "notify"}, stageNames(manifest.Releases[0].Stages))
+			assert.Equal(t, "production", manifest.Releases[0].Stages[0].CustomProperties["namespace"])
+		}
+	})
+
+	t.Run("UsesTemplatesForBots", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), templates+`
+bots:
+  cleanup:
+    template: kubernetes
+    templateParams:
+      namespace: staging
+    stages:
+      deploy:
+        image: extensions/gke:dev`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Bots)) {
+			assert.Equal(t, []string{"deploy", "notify"}, stageNames(manifest.Bots[0].Stages))
+			assert.Equal(t, "extensions/gke:dev", manifest.Bots[0].Stages[0].ContainerImage)
+			assert.Equal(t, "staging", manifest.Bots[0].Stages[0].CustomProperties["namespace"])
+		}
+	})
+
+	t.Run("IsTheSameWhenReadingMarshalledManifestWithBotUsingTemplateAgain", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), templates+`
+bots:
+  cleanup:
+    template: kubernetes
+    templateParams:
+      namespace: staging
+    stages:
+      deploy:
+        remove: true`, true)
+		assert.Nil(t, err)
+
+		output, err := yaml.Marshal(manifest)
+		assert.Nil(t, err)
+
+		// act
+		reread, err := ReadManifest(GetDefaultManifestPreferences(), string(output), true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(reread.Bots)) {
+			assert.Equal(t, []string{"notify"}, stageNames(reread.Bots[0].Stages))
+		}
+	})
+
+	t.Run("ReturnsErrorForUnknownReleaseTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), templates+`
+releases:
+  production:
+    template: kubernetes-canary`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeTemplateInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "releases.production.template", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForUnknownBotTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), templates+`
+bots:
+  cleanup:
+    template: kubernetes-canary
+    stages:
+      cleanup:
+        image: alpine`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "bots.cleanup.template", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForTemplateExtendingUnknownTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    extends: base
+    stages:
+      deploy:
+        image: extensions/gke:stable`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "releaseTemplates.kubernetes.extends", result.Errors()[0].Path)
+			assert.Equal(t, 7, result.Errors()[0].Line)
+		}
+	})
+
+	t.Run("ReturnsErrorForTemplatesExtendingEachOther", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    extends: canary
+  canary:
+    extends: kubernetes`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 2, len(result.Errors())) {
+			assert.Contains(t, result.Errors()[0].Error(), "kubernetes > canary > kubernetes")
+		}
+	})
+}
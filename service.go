@@ -156,3 +156,12 @@ func (readiness *ReadinessProbe) SetDefaults(serviceName string) {
 		}
 	}
 }
+
+// usesDeprecatedProperties indicates whether the probe is configured with the legacy path, port, protocol and hostname properties
+func (readiness *ReadinessProbe) usesDeprecatedProperties() bool {
+	if readiness.HttpGet != nil || readiness.Exec != nil {
+		return false
+	}
+
+	return readiness.Path != "" || readiness.Port != 0 || readiness.Protocol != "" || readiness.Hostname != ""
+}
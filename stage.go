@@ -3,6 +3,7 @@ package manifest
 import (
 	"fmt"
 
+	"github.com/jinzhu/copier"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -19,6 +20,10 @@ type ZiplineeStage struct {
 	AutoInjected            bool                   `yaml:"autoInjected,omitempty" json:",omitempty"`
 	ParallelStages          []*ZiplineeStage       `yaml:"parallelStages,omitempty" json:",omitempty"`
 	Services                []*ZiplineeService     `yaml:"services,omitempty" json:",omitempty"`
+	Before                  string                 `yaml:"before,omitempty" json:",omitempty"`
+	After                   string                 `yaml:"after,omitempty" json:",omitempty"`
+	Remove                  bool                   `yaml:"remove,omitempty" json:",omitempty"`
+	Template                string                 `yaml:"template,omitempty" json:",omitempty"`
 	CustomProperties        map[string]interface{} `yaml:",inline" json:",omitempty"`
 }
 
@@ -37,6 +42,10 @@ func (stage *ZiplineeStage) UnmarshalYAML(unmarshal func(interface{}) error) (er
 		AutoInjected            bool                   `yaml:"autoInjected,omitempty"`
 		ParallelStages          yaml.MapSlice          `yaml:"parallelStages"`
 		Services                []*ZiplineeService     `yaml:"services,omitempty"`
+		Before                  string                 `yaml:"before,omitempty"`
+		After                   string                 `yaml:"after,omitempty"`
+		Remove                  bool                   `yaml:"remove,omitempty"`
+		Template                string                 `yaml:"template,omitempty"`
 		CustomProperties        map[string]interface{} `yaml:",inline"`
 	}
 
@@ -56,6 +65,10 @@ func (stage *ZiplineeStage) UnmarshalYAML(unmarshal func(interface{}) error) (er
 	stage.EnvVars = aux.EnvVars
 	stage.AutoInjected = aux.AutoInjected
 	stage.Services = aux.Services
+	stage.Before = aux.Before
+	stage.After = aux.After
+	stage.Remove = aux.Remove
+	stage.Template = aux.Template
 
 	for _, mi := range aux.ParallelStages {
 
@@ -66,7 +79,7 @@ func (stage *ZiplineeStage) UnmarshalYAML(unmarshal func(interface{}) error) (er
 
 		var innerStage *ZiplineeStage
 		if err := yaml.Unmarshal(bytes, &innerStage); err != nil {
-			return err
+			return wrapManifestError(fmt.Sprintf("parallelStages.%v", mi.Key), err)
 		}
 		if innerStage == nil {
 			innerStage = &ZiplineeStage{}
@@ -124,19 +137,19 @@ func (stage *ZiplineeStage) Validate() (err error) {
 
 	if len(stage.ParallelStages) > 0 {
 		if stage.ContainerImage != "" {
-			return fmt.Errorf("Stage %v cannot use parameters parallelStages and image at the same time", stage.Name)
+			return wrapManifestError("image", fmt.Errorf("Stage %v cannot use parameters parallelStages and image at the same time", stage.Name))
 		}
 		if stage.Shell != "" {
-			return fmt.Errorf("Stage %v cannot use parameters parallelStages and shell at the same time", stage.Name)
+			return wrapManifestError("shell", fmt.Errorf("Stage %v cannot use parameters parallelStages and shell at the same time", stage.Name))
 		}
 		if stage.WorkingDirectory != "" {
-			return fmt.Errorf("Stage %v cannot use parameters parallelStages and workDir at the same time", stage.Name)
+			return wrapManifestError("workDir", fmt.Errorf("Stage %v cannot use parameters parallelStages and workDir at the same time", stage.Name))
 		}
 		if len(stage.Commands) > 0 {
-			return fmt.Errorf("Stage %v cannot use parameters parallelStages and commands at the same time", stage.Name)
+			return wrapManifestError("commands", fmt.Errorf("Stage %v cannot use parameters parallelStages and commands at the same time", stage.Name))
 		}
 		if len(stage.EnvVars) > 0 {
-			return fmt.Errorf("Stage %v cannot use parameters parallelStages and env at the same time", stage.Name)
+			return wrapManifestError("env", fmt.Errorf("Stage %v cannot use parameters parallelStages and env at the same time", stage.Name))
 		}
 	} else {
 		if stage.ContainerImage == "" && len(stage.Services) == 0 {
@@ -146,3 +159,11 @@ func (stage *ZiplineeStage) Validate() (err error) {
 
 	return nil
 }
+
+// DeepCopy provides a copy of all nested pointers
+func (stage ZiplineeStage) DeepCopy() (target ZiplineeStage) {
+
+	copier.CopyWithOption(&target, stage, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+
+	return
+}
@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"fmt"
+)
+
+// mergeStages merges stages into the base stages by name; a stage with the name of a base stage overrides the properties it sets,
+// a stage with remove removes the base stage and other stages are added. Stages with before or after are moved to or inserted at
+// that position, other added stages follow the stage preceding them in stages or otherwise precede the stage following them.
+// Stages with a before, after or remove that can't be applied keep it, so validation can report them
+func mergeStages(base, stages []*ZiplineeStage) (merged []*ZiplineeStage) {
+
+	merged = append(merged, base...)
+
+	// added stages without a stage preceding them are inserted before the next stage that's already there
+	pending := []*ZiplineeStage{}
+	previous := ""
+
+	for _, s := range stages {
+		if s == nil {
+			continue
+		}
+
+		index := stageIndex(merged, s.Name)
+
+		if s.Remove {
+			if index < 0 || s.Before != "" || s.After != "" {
+				merged = append(merged, s)
+				continue
+			}
+			merged = append(merged[:index], merged[index+1:]...)
+			if previous == s.Name {
+				previous = ""
+				if index > 0 {
+					previous = merged[index-1].Name
+				}
+			}
+			continue
+		}
+
+		stage := s
+		if index >= 0 {
+			stage = mergeStage(merged[index], s)
+		}
+
+		switch {
+		case s.Before != "" && s.After != "":
+			// can't be applied, leave in place or add at the end to be reported by validation
+			if index < 0 {
+				merged = append(merged, stage)
+			} else {
+				merged[index] = stage
+			}
+			continue
+
+		case s.Before != "" || s.After != "":
+			target := s.Before
+			if target == "" {
+				target = s.After
+			}
+			if target == s.Name || stageIndex(merged, target) < 0 {
+				if index < 0 {
+					merged = append(merged, stage)
+				} else {
+					merged[index] = stage
+				}
+				continue
+			}
+
+			if index >= 0 {
+				merged = append(merged[:index], merged[index+1:]...)
+			}
+			position := stageIndex(merged, target)
+			if s.After != "" {
+				position++
+			}
+			stage.Before = ""
+			stage.After = ""
+			merged = insertStage(merged, position, stage)
+
+		case index >= 0:
+			merged[index] = stage
+
+		case previous != "":
+			merged = insertStage(merged, stageIndex(merged, previous)+1, stage)
+
+		default:
+			pending = append(pending, stage)
+			continue
+		}
+
+		if len(pending) > 0 {
+			position := stageIndex(merged, stage.Name)
+			for _, p := range pending {
+				merged = insertStage(merged, position, p)
+				position++
+			}
+			pending = []*ZiplineeStage{}
+		}
+		previous = stage.Name
+	}
+
+	return append(merged, pending...)
+}
+
+// mergeStage returns a copy of the base stage with the properties set in the stage overriding those of the base stage, merging env
+// vars and custom properties by key and parallel stages by name
+func mergeStage(base, stage *ZiplineeStage) *ZiplineeStage {
+
+	merged := *base
+	merged.Before = stage.Before
+	merged.After = stage.After
+
+	if stage.Template != "" {
+		merged.Template = stage.Template
+	}
+	if stage.ContainerImage != "" {
+		merged.ContainerImage = stage.ContainerImage
+	}
+	if stage.Shell != "" {
+		merged.Shell = stage.Shell
+	}
+	if stage.WorkingDirectory != "" {
+		merged.WorkingDirectory = stage.WorkingDirectory
+	}
+	if len(stage.Commands) > 0 {
+		merged.Commands = stage.Commands
+	}
+	if stage.RunCommandsInForeground {
+		merged.RunCommandsInForeground = true
+	}
+	if stage.When != "" {
+		merged.When = stage.When
+	}
+	if len(stage.EnvVars) > 0 {
+		merged.EnvVars = map[string]string{}
+		for k, v := range base.EnvVars {
+			merged.EnvVars[k] = v
+		}
+		for k, v := range stage.EnvVars {
+			merged.EnvVars[k] = v
+		}
+	}
+	if len(stage.ParallelStages) > 0 {
+		merged.ParallelStages = mergeStages(base.ParallelStages, stage.ParallelStages)
+	}
+	if len(stage.Services) > 0 {
+		merged.Services = stage.Services
+	}
+	if len(stage.CustomProperties) > 0 {
+		merged.CustomProperties = map[string]interface{}{}
+		for k, v := range base.CustomProperties {
+			merged.CustomProperties[k] = v
+		}
+		for k, v := range stage.CustomProperties {
+			merged.CustomProperties[k] = v
+		}
+	}
+
+	return &merged
+}
+
+// validateStageMerge returns an error for a stage with a before, after or remove that's left after merging
+func validateStageMerge(stage *ZiplineeStage) (err error) {
+	switch {
+	case stage.Remove && (stage.Before != "" || stage.After != ""):
+		return fmt.Errorf("Stage %v can't set remove together with before or after", stage.Name)
+	case stage.Remove:
+		return wrapManifestError("remove", fmt.Errorf("Stage %v can't be removed, there's no template stage with that name", stage.Name))
+	case stage.Before != "" && stage.After != "":
+		return fmt.Errorf("Stage %v can't set both before and after", stage.Name)
+	case stage.Before != "":
+		return wrapManifestError("before", fmt.Errorf("Stage %v can't be inserted before %v, there's no template stage with that name", stage.Name, stage.Before))
+	case stage.After != "":
+		return wrapManifestError("after", fmt.Errorf("Stage %v can't be inserted after %v, there's no template stage with that name", stage.Name, stage.After))
+	}
+
+	return nil
+}
+
+func stageIndex(stages []*ZiplineeStage, name string) int {
+	for i, s := range stages {
+		if s.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func insertStage(stages []*ZiplineeStage, position int, stage *ZiplineeStage) []*ZiplineeStage {
+	stages = append(stages, nil)
+	copy(stages[position+1:], stages[position:])
+	stages[position] = stage
+
+	return stages
+}
@@ -0,0 +1,289 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func stageNames(stages []*ZiplineeStage) (names []string) {
+	for _, s := range stages {
+		names = append(names, s.Name)
+	}
+	return
+}
+
+func TestMergeStages(t *testing.T) {
+
+	base := func() []*ZiplineeStage {
+		return []*ZiplineeStage{
+			{Name: "deploy", ContainerImage: "extensions/gke:stable", EnvVars: map[string]string{"A": "a"}, CustomProperties: map[string]interface{}{"namespace": "ziplinee"}},
+			{Name: "notify", ContainerImage: "extensions/slack-build-status:stable"},
+		}
+	}
+
+	t.Run("OverridesPropertiesOfStageWithSameName", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "deploy", ContainerImage: "extensions/gke:dev", EnvVars: map[string]string{"B": "b"}, CustomProperties: map[string]interface{}{"visibility": "public"}}})
+
+		assert.Equal(t, []string{"deploy", "notify"}, stageNames(merged))
+		assert.Equal(t, "extensions/gke:dev", merged[0].ContainerImage)
+		assert.Equal(t, map[string]string{"A": "a", "B": "b"}, merged[0].EnvVars)
+		assert.Equal(t, map[string]interface{}{"namespace": "ziplinee", "visibility": "public"}, merged[0].CustomProperties)
+	})
+
+	t.Run("DoesNotChangeBaseStages", func(t *testing.T) {
+
+		stages := base()
+
+		// act
+		mergeStages(stages, []*ZiplineeStage{{Name: "deploy", ContainerImage: "extensions/gke:dev"}, {Name: "notify", Remove: true}})
+
+		assert.Equal(t, []string{"deploy", "notify"}, stageNames(stages))
+		assert.Equal(t, "extensions/gke:stable", stages[0].ContainerImage)
+	})
+
+	t.Run("RemovesStageWithRemove", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "deploy", Remove: true}})
+
+		assert.Equal(t, []string{"notify"}, stageNames(merged))
+	})
+
+	t.Run("InsertsStageBefore", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curl", Before: "notify"}})
+
+		assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(merged))
+		assert.Equal(t, "", merged[1].Before)
+	})
+
+	t.Run("InsertsStageAfter", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curl", After: "notify"}})
+
+		assert.Equal(t, []string{"deploy", "notify", "smoke-test"}, stageNames(merged))
+	})
+
+	t.Run("MovesExistingStage", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "deploy", After: "notify"}})
+
+		assert.Equal(t, []string{"notify", "deploy"}, stageNames(merged))
+		assert.Equal(t, "extensions/gke:stable", merged[1].ContainerImage)
+	})
+
+	t.Run("AddsStageAfterPrecedingStage", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "deploy"}, {Name: "smoke-test", ContainerImage: "curl"}})
+
+		assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(merged))
+	})
+
+	t.Run("AddsStageBeforeFollowingStage", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "prepare", ContainerImage: "alpine"}, {Name: "notify"}})
+
+		assert.Equal(t, []string{"deploy", "prepare", "notify"}, stageNames(merged))
+	})
+
+	t.Run("AddsStageAtEnd", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curl"}})
+
+		assert.Equal(t, []string{"deploy", "notify", "smoke-test"}, stageNames(merged))
+	})
+
+	t.Run("KeepsBeforeForUnknownStage", func(t *testing.T) {
+
+		// act
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curl", Before: "rollback"}})
+
+		assert.Equal(t, []string{"deploy", "notify", "smoke-test"}, stageNames(merged))
+		assert.Equal(t, "rollback", merged[2].Before)
+	})
+
+	t.Run("IsTheSameWhenMergingMergedStagesAgain", func(t *testing.T) {
+
+		merged := mergeStages(base(), []*ZiplineeStage{{Name: "smoke-test", ContainerImage: "curl", Before: "notify"}, {Name: "cleanup", ContainerImage: "alpine"}})
+
+		// act
+		remerged := mergeStages(base(), merged)
+
+		assert.Equal(t, []string{"deploy", "smoke-test", "cleanup", "notify"}, stageNames(merged))
+		assert.Equal(t, stageNames(merged), stageNames(remerged))
+	})
+}
+
+func TestValidateStageMerge(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		stage ZiplineeStage
+		valid bool
+	}{
+		{"WithoutDirectives", ZiplineeStage{Name: "deploy"}, true},
+		{"WithRemove", ZiplineeStage{Name: "deploy", Remove: true}, false},
+		{"WithBefore", ZiplineeStage{Name: "deploy", Before: "notify"}, false},
+		{"WithAfter", ZiplineeStage{Name: "deploy", After: "notify"}, false},
+		{"WithBeforeAndAfter", ZiplineeStage{Name: "deploy", Before: "notify", After: "build"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// act
+			err := validateStageMerge(&tc.stage)
+
+			assert.Equal(t, tc.valid, err == nil, "error: %v", err)
+		})
+	}
+}
+
+func TestReadManifestWithReleaseStagesMergedIntoTemplate(t *testing.T) {
+
+	template := `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    stages:
+      deploy:
+        image: extensions/gke:stable
+      notify:
+        image: extensions/slack-build-status:stable
+`
+
+	t.Run("MergesStagesIntoTemplateStages", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  production:
+    template: kubernetes
+    stages:
+      deploy:
+        image: extensions/gke:dev
+      smoke-test:
+        image: curlimages/curl
+        before: notify`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) {
+			assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(manifest.Releases[0].Stages))
+			assert.Equal(t, "extensions/gke:dev", manifest.Releases[0].Stages[0].ContainerImage)
+		}
+	})
+
+	t.Run("MarshalsMergedStages", func(t *testing.T) {
+
+		var manifest ZiplineeManifest
+		err := yaml.Unmarshal([]byte(template+`
+releases:
+  production:
+    template: kubernetes
+    stages:
+      smoke-test:
+        image: curlimages/curl
+        before: notify
+      notify:
+        remove: true`), &manifest)
+		assert.Nil(t, err)
+
+		// act
+		output, err := yaml.Marshal(manifest.Releases[0])
+
+		assert.Nil(t, err)
+		assert.Equal(t, `stages:
+  deploy:
+    image: extensions/gke:stable
+  smoke-test:
+    image: curlimages/curl
+template: kubernetes
+`, string(output))
+	})
+
+	t.Run("IsTheSameWhenReadingMarshalledManifestWithRemovedStagesAgain", func(t *testing.T) {
+
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    stages:
+      prepare:
+        image: alpine
+      deploy:
+        image: extensions/gke:stable
+      notify:
+        image: extensions/slack-build-status:stable
+releases:
+  production:
+    template: kubernetes
+    stages:
+      prepare:
+        remove: true
+      smoke-test:
+        image: curlimages/curl
+        before: notify`, true)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(manifest.Releases[0].Stages))
+
+		output, err := yaml.Marshal(manifest)
+		assert.Nil(t, err)
+
+		// act
+		reread, err := ReadManifest(GetDefaultManifestPreferences(), string(output), true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(reread.Releases)) {
+			assert.Equal(t, []string{"deploy", "smoke-test", "notify"}, stageNames(reread.Releases[0].Stages))
+		}
+	})
+
+	t.Run("ReturnsErrorForRemovingUnknownStage", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  production:
+    template: kubernetes
+    stages:
+      rollback:
+        remove: true`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "releases.production.stages.rollback.remove", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForBeforeOnBuildStage", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+  test:
+    image: golang
+    before: build`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "stages.test.before", result.Errors()[0].Path)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package manifest
+
+import (
+	"fmt"
+)
+
+// InitFromTemplate uses the values of the stage template for values the stage doesn't set itself, for the stage and its parallel stages
+func (stage *ZiplineeStage) InitFromTemplate(stageTemplates map[string]*ZiplineeStage) {
+
+	if stage.Template != "" {
+		if stageTemplate, found := stageTemplates[stage.Template]; found && stageTemplate != nil {
+
+			// deep copy so there's no pointers shared with other stages
+			template := stageTemplate.DeepCopy()
+
+			merged := mergeStage(&template, stage)
+			merged.Name = stage.Name
+			merged.Template = stage.Template
+			merged.Remove = stage.Remove
+
+			*stage = *merged
+		}
+	}
+
+	for _, s := range stage.ParallelStages {
+		if s != nil {
+			s.InitFromTemplate(stageTemplates)
+		}
+	}
+}
+
+// initStagesFromTemplates applies stage templates to the stages of the manifest, releases and bots
+func (c *ZiplineeManifest) initStagesFromTemplates() {
+	if len(c.StageTemplates) == 0 {
+		return
+	}
+
+	stageTemplates := map[string]*ZiplineeStage{}
+	for _, s := range c.StageTemplates {
+		stageTemplates[s.Name] = s
+	}
+
+	stages := [][]*ZiplineeStage{c.Stages}
+	for _, r := range c.Releases {
+		stages = append(stages, r.Stages)
+	}
+	for _, b := range c.Bots {
+		stages = append(stages, b.Stages)
+	}
+	for _, section := range stages {
+		for _, s := range section {
+			if s != nil {
+				s.InitFromTemplate(stageTemplates)
+			}
+		}
+	}
+}
+
+// validateStageTemplate checks whether the stage template the stage uses exists
+func (c *ZiplineeManifest) validateStageTemplate(stage *ZiplineeStage) (err error) {
+	if stage.Template == "" {
+		return nil
+	}
+	for _, s := range c.StageTemplates {
+		if s.Name == stage.Template {
+			return nil
+		}
+	}
+
+	return wrapManifestError("template", fmt.Errorf("Stage %v uses template %v, which does not exist in stageTemplates", stage.Name, stage.Template))
+}
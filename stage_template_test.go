@@ -0,0 +1,239 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestZiplineeStageInitFromTemplate(t *testing.T) {
+
+	stageTemplates := map[string]*ZiplineeStage{
+		"go-build": {
+			Name:             "go-build",
+			ContainerImage:   "golang:1.22",
+			Commands:         []string{"go test ./...", "go build ./..."},
+			EnvVars:          map[string]string{"CGO_ENABLED": "0"},
+			CustomProperties: map[string]interface{}{"cpu": map[string]interface{}{"request": "1"}},
+		},
+	}
+
+	t.Run("UsesTemplateValuesForValuesNotSetOnStage", func(t *testing.T) {
+
+		stage := ZiplineeStage{Name: "build", Template: "go-build", ContainerImage: "golang:1.23", EnvVars: map[string]string{"GOOS": "linux"}}
+
+		// act
+		stage.InitFromTemplate(stageTemplates)
+
+		assert.Equal(t, "build", stage.Name)
+		assert.Equal(t, "go-build", stage.Template)
+		assert.Equal(t, "golang:1.23", stage.ContainerImage)
+		assert.Equal(t, []string{"go test ./...", "go build ./..."}, stage.Commands)
+		assert.Equal(t, map[string]string{"CGO_ENABLED": "0", "GOOS": "linux"}, stage.EnvVars)
+	})
+
+	t.Run("AppliesTemplatesToParallelStages", func(t *testing.T) {
+
+		stage := ZiplineeStage{Name: "build", ParallelStages: []*ZiplineeStage{{Name: "build-linux", Template: "go-build"}}}
+
+		// act
+		stage.InitFromTemplate(stageTemplates)
+
+		assert.Equal(t, "golang:1.22", stage.ParallelStages[0].ContainerImage)
+		assert.Equal(t, "build-linux", stage.ParallelStages[0].Name)
+	})
+
+	t.Run("DoesNotShareValuesWithTemplate", func(t *testing.T) {
+
+		stage := ZiplineeStage{Name: "build", Template: "go-build"}
+
+		// act
+		stage.InitFromTemplate(stageTemplates)
+		stage.Commands[0] = "go vet ./..."
+		stage.CustomProperties["cpu"].(map[string]interface{})["request"] = "2"
+
+		assert.Equal(t, "go test ./...", stageTemplates["go-build"].Commands[0])
+		assert.Equal(t, "1", stageTemplates["go-build"].CustomProperties["cpu"].(map[string]interface{})["request"])
+	})
+
+	t.Run("LeavesStageWithUnknownTemplateAsIs", func(t *testing.T) {
+
+		stage := ZiplineeStage{Name: "build", Template: "rust-build"}
+
+		// act
+		stage.InitFromTemplate(stageTemplates)
+
+		assert.Equal(t, "", stage.ContainerImage)
+	})
+}
+
+func TestReadManifestWithStageTemplates(t *testing.T) {
+
+	stageTemplates := `
+stageTemplates:
+  go-build:
+    image: golang:1.22
+    commands:
+    - go build ./...
+  docker-push:
+    image: extensions/docker:stable
+    action: push
+`
+
+	t.Run("AppliesStageTemplatesToStagesReleasesAndBots", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), stageTemplates+`
+stages:
+  build:
+    template: go-build
+  push:
+    template: docker-push
+    repositories:
+    - ziplineeci
+releaseTemplates:
+  kubernetes:
+    stages:
+      push:
+        template: docker-push
+releases:
+  production:
+    template: kubernetes
+    stages:
+      deploy:
+        template: go-build
+        commands:
+        - go run ./cmd/deploy
+bots:
+  rebuild:
+    stages:
+      build:
+        parallelStages:
+          build-linux:
+            template: go-build`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(manifest.Stages)) {
+			assert.Equal(t, "golang:1.22", manifest.Stages[0].ContainerImage)
+			assert.Equal(t, "/bin/sh", manifest.Stages[0].Shell)
+			assert.Equal(t, "extensions/docker:stable", manifest.Stages[1].ContainerImage)
+			assert.Equal(t, "push", manifest.Stages[1].CustomProperties["action"])
+			assert.Equal(t, []interface{}{"ziplineeci"}, manifest.Stages[1].CustomProperties["repositories"])
+		}
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.Equal(t, 2, len(manifest.Releases[0].Stages)) {
+			assert.Equal(t, "extensions/docker:stable", manifest.Releases[0].Stages[0].ContainerImage)
+			assert.Equal(t, "golang:1.22", manifest.Releases[0].Stages[1].ContainerImage)
+			assert.Equal(t, []string{"go run ./cmd/deploy"}, manifest.Releases[0].Stages[1].Commands)
+		}
+		if assert.Equal(t, 1, len(manifest.Bots)) {
+			assert.Equal(t, "golang:1.22", manifest.Bots[0].Stages[0].ParallelStages[0].ContainerImage)
+		}
+	})
+
+	t.Run("AppliesStageTemplateToReleaseStageOverridingTemplateStage", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), stageTemplates+`
+stages:
+  build:
+    template: go-build
+releaseTemplates:
+  kubernetes:
+    stages:
+      deploy:
+        image: alpine
+      push:
+        parallelStages:
+          push-eu:
+            image: alpine
+releases:
+  production:
+    template: kubernetes
+    stages:
+      deploy:
+        template: go-build
+      push:
+        parallelStages:
+          push-eu:
+            template: docker-push`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(manifest.Releases)) && assert.Equal(t, 2, len(manifest.Releases[0].Stages)) {
+			deploy := manifest.Releases[0].Stages[0]
+			assert.Equal(t, "go-build", deploy.Template)
+			assert.Equal(t, "alpine", deploy.ContainerImage)
+			assert.Equal(t, []string{"go build ./..."}, deploy.Commands)
+
+			push := manifest.Releases[0].Stages[1]
+			if assert.Equal(t, 1, len(push.ParallelStages)) {
+				assert.Equal(t, "docker-push", push.ParallelStages[0].Template)
+				assert.Equal(t, "alpine", push.ParallelStages[0].ContainerImage)
+				assert.Equal(t, "push", push.ParallelStages[0].CustomProperties["action"])
+			}
+		}
+	})
+
+	t.Run("MarshalsStageTemplatesAndMergedStages", func(t *testing.T) {
+
+		var manifest ZiplineeManifest
+		err := yaml.Unmarshal([]byte(stageTemplates+`
+stages:
+  build:
+    template: go-build`), &manifest)
+		assert.Nil(t, err)
+
+		// act
+		output, err := yaml.Marshal(manifest)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `stages:
+  build:
+    image: golang:1.22
+    commands:
+    - go build ./...
+    template: go-build
+stageTemplates:
+  go-build:
+    image: golang:1.22
+    commands:
+    - go build ./...
+  docker-push:
+    image: extensions/docker:stable
+    action: push
+`, string(output))
+	})
+
+	t.Run("ReturnsErrorForUnknownStageTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), stageTemplates+`
+stages:
+  build:
+    template: rust-build
+    image: rust`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeTemplateInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "stages.build.template", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForStageTemplateUsingTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), stageTemplates+`
+  go-test:
+    template: go-build
+stages:
+  build:
+    template: go-build`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "stageTemplates.go-test.template", result.Errors()[0].Path)
+		}
+	})
+}
@@ -2,8 +2,6 @@ package manifest
 
 import (
 	"encoding/json"
-	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -80,8 +78,8 @@ func (s StringOrStringArray) MarshalJSON() ([]byte, error) {
 // Contains test whether the compared value matches one of the values
 func (s StringOrStringArray) Contains(value string) bool {
 	for _, v := range s.Values {
-		pattern := fmt.Sprintf("^%v$", strings.TrimSpace(v))
-		match, err := regexp.MatchString(pattern, value)
+		pattern := "^" + strings.TrimSpace(v) + "$"
+		match, err := compiledRegexes.MatchString(pattern, value)
 		if err == nil && match {
 			return true
 		}
@@ -0,0 +1,221 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templateParameterPlaceholderRegex matches placeholders like {{ params.namespace }} in the stages of a release template
+var templateParameterPlaceholderRegex = regexp.MustCompile(`{{\s*params\.([a-zA-Z][a-zA-Z0-9_-]*)\s*}}`)
+
+// ZiplineeTemplateParameter declares a parameter of a release template, that releases using the template set in templateParams
+type ZiplineeTemplateParameter struct {
+	Name        string `yaml:"name,omitempty" json:"name,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Default     string `yaml:"default,omitempty" json:"default,omitempty"`
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// Validate checks if ZiplineeTemplateParameter is valid
+func (p *ZiplineeTemplateParameter) Validate() (err error) {
+	if p.Name == "" {
+		return fmt.Errorf("Set name for parameters, it's used in placeholders like {{ params.name }} and in templateParams of releases")
+	}
+	if !manualInputNameRegex.MatchString(p.Name) {
+		return fmt.Errorf("Invalid parameter name %v, start with a letter and only use letters, digits, dashes and underscores", p.Name)
+	}
+	if p.Required && p.Default != "" {
+		return fmt.Errorf("Do not set a default for required parameter %v", p.Name)
+	}
+
+	return nil
+}
+
+// validateParameters checks the declared parameters and whether all placeholders in the stages refer to one of them
+func (releaseTemplate *ZiplineeReleaseTemplate) validateParameters() (err error) {
+	declared := map[string]bool{}
+	for i, p := range releaseTemplate.Parameters {
+		if p == nil {
+			continue
+		}
+		err = p.Validate()
+		if err == nil && declared[p.Name] {
+			err = fmt.Errorf("Parameter %v is declared more than once", p.Name)
+		}
+		if err != nil {
+			return wrapManifestError(fmt.Sprintf("parameters[%v]", i), err)
+		}
+		declared[p.Name] = true
+	}
+
+	for _, s := range releaseTemplate.Stages {
+		for _, name := range s.templateParameterReferences() {
+			if !declared[name] {
+				return wrapManifestError(fmt.Sprintf("stages.%v", s.Name), fmt.Errorf("Stage %v uses parameter %v, which isn't declared in parameters of the template", s.Name, name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTemplateParams checks the template exists and the params set all required parameters of the template and no undeclared ones
+func validateTemplateParams(templateName string, params map[string]string, releaseTemplates map[string]*ZiplineeReleaseTemplate) (err error) {
+	if templateName == "" {
+		if len(params) > 0 {
+			return wrapManifestError("templateParams", fmt.Errorf("Only set templateParams together with template"))
+		}
+		return nil
+	}
+
+	releaseTemplate := resolveReleaseTemplate(templateName, releaseTemplates)
+	if releaseTemplate == nil {
+		return wrapManifestError("template", fmt.Errorf("Template %v does not exist, set template to the name of one of the releaseTemplates", templateName))
+	}
+
+	declared := map[string]bool{}
+	for _, p := range releaseTemplate.Parameters {
+		if p == nil {
+			continue
+		}
+		declared[p.Name] = true
+		if _, ok := params[p.Name]; !ok && p.Required {
+			return wrapManifestError("templateParams", fmt.Errorf("Set parameter %v of template %v in templateParams", p.Name, releaseTemplate.Name))
+		}
+	}
+
+	// loop params in a fixed order so the same error is returned every time
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !declared[name] {
+			return wrapManifestError(fmt.Sprintf("templateParams.%v", name), fmt.Errorf("Parameter %v is not declared in template %v", name, releaseTemplate.Name))
+		}
+	}
+
+	return nil
+}
+
+// templateParameterValues returns the value for each declared parameter, taken from the params or the parameter's default
+func (releaseTemplate *ZiplineeReleaseTemplate) templateParameterValues(params map[string]string) map[string]string {
+	values := map[string]string{}
+	for _, p := range releaseTemplate.Parameters {
+		if p == nil {
+			continue
+		}
+		if value, ok := params[p.Name]; ok {
+			values[p.Name] = value
+		} else if !p.Required {
+			values[p.Name] = p.Default
+		}
+	}
+
+	return values
+}
+
+// replaceTemplateParameters replaces placeholders for which there's a value; others are left as is so they can be reported
+func replaceTemplateParameters(value string, values map[string]string) string {
+	if !strings.Contains(value, "{{") {
+		return value
+	}
+
+	return templateParameterPlaceholderRegex.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := templateParameterPlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		return placeholder
+	})
+}
+
+// replaceTemplateParameters replaces placeholders in the stage's properties, env vars, custom properties, services and parallel stages
+func (stage *ZiplineeStage) replaceTemplateParameters(values map[string]string) {
+	stage.replaceStrings(func(s string) string {
+		return replaceTemplateParameters(s, values)
+	})
+}
+
+// templateParameterReferences returns the names of the parameters used in placeholders in the stage, in order of first use
+func (stage *ZiplineeStage) templateParameterReferences() (names []string) {
+	found := map[string]bool{}
+	stage.replaceStrings(func(s string) string {
+		for _, match := range templateParameterPlaceholderRegex.FindAllStringSubmatch(s, -1) {
+			if !found[match[1]] {
+				found[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+		return s
+	})
+
+	return
+}
+
+// replaceStrings applies replace to all string values of the stage that can contain placeholders
+func (stage *ZiplineeStage) replaceStrings(replace func(string) string) {
+	stage.ContainerImage = replace(stage.ContainerImage)
+	stage.Shell = replace(stage.Shell)
+	stage.WorkingDirectory = replace(stage.WorkingDirectory)
+	stage.When = replace(stage.When)
+	for i, c := range stage.Commands {
+		stage.Commands[i] = replace(c)
+	}
+	for k, v := range stage.EnvVars {
+		stage.EnvVars[k] = replace(v)
+	}
+	for k, v := range stage.CustomProperties {
+		stage.CustomProperties[k] = replaceStringsInValue(v, replace)
+	}
+
+	for _, svc := range stage.Services {
+		if svc == nil {
+			continue
+		}
+		svc.ContainerImage = replace(svc.ContainerImage)
+		svc.Shell = replace(svc.Shell)
+		svc.When = replace(svc.When)
+		for i, c := range svc.Commands {
+			svc.Commands[i] = replace(c)
+		}
+		for k, v := range svc.EnvVars {
+			svc.EnvVars[k] = replace(v)
+		}
+		for k, v := range svc.CustomProperties {
+			svc.CustomProperties[k] = replaceStringsInValue(v, replace)
+		}
+	}
+
+	for _, s := range stage.ParallelStages {
+		if s != nil {
+			s.replaceStrings(replace)
+		}
+	}
+}
+
+// replaceStringsInValue applies replace to the strings in nested custom properties
+func replaceStringsInValue(value interface{}, replace func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replace(v)
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = replaceStringsInValue(item, replace)
+		}
+	case map[interface{}]interface{}:
+		for k, item := range v {
+			v[k] = replaceStringsInValue(item, replace)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = replaceStringsInValue(item, replace)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,207 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZiplineeTemplateParameterValidate(t *testing.T) {
+
+	testCases := []struct {
+		name      string
+		parameter ZiplineeTemplateParameter
+		valid     bool
+	}{
+		{"Valid", ZiplineeTemplateParameter{Name: "namespace"}, true},
+		{"ValidWithDefault", ZiplineeTemplateParameter{Name: "replicas", Default: "3"}, true},
+		{"ValidRequired", ZiplineeTemplateParameter{Name: "cluster", Required: true}, true},
+		{"EmptyName", ZiplineeTemplateParameter{}, false},
+		{"NameWithDots", ZiplineeTemplateParameter{Name: "cluster.name"}, false},
+		{"RequiredWithDefault", ZiplineeTemplateParameter{Name: "cluster", Required: true, Default: "production"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// act
+			err := tc.parameter.Validate()
+
+			assert.Equal(t, tc.valid, err == nil, "error: %v", err)
+		})
+	}
+}
+
+func TestReplaceTemplateParameters(t *testing.T) {
+	t.Run("ReplacesPlaceholdersWithValues", func(t *testing.T) {
+
+		// act
+		value := replaceTemplateParameters("kubectl -n {{ params.namespace }} --context={{params.cluster}}", map[string]string{"namespace": "ziplinee", "cluster": "production"})
+
+		assert.Equal(t, "kubectl -n ziplinee --context=production", value)
+	})
+
+	t.Run("LeavesPlaceholdersWithoutValue", func(t *testing.T) {
+
+		// act
+		value := replaceTemplateParameters("kubectl -n {{ params.namespace }}", map[string]string{})
+
+		assert.Equal(t, "kubectl -n {{ params.namespace }}", value)
+	})
+
+	t.Run("LeavesOtherPlaceholders", func(t *testing.T) {
+
+		// act
+		value := replaceTemplateParameters("{{branch}}-{{ params.namespace }}", map[string]string{"namespace": "ziplinee"})
+
+		assert.Equal(t, "{{branch}}-ziplinee", value)
+	})
+}
+
+func TestZiplineeStageReplaceTemplateParameters(t *testing.T) {
+	t.Run("ReplacesPlaceholdersInPropertiesEnvVarsCustomPropertiesServicesAndParallelStages", func(t *testing.T) {
+
+		stage := ZiplineeStage{
+			ContainerImage: "extensions/gke:{{ params.track }}",
+			Commands:       []string{"echo {{ params.namespace }}"},
+			EnvVars:        map[string]string{"NAMESPACE": "{{ params.namespace }}"},
+			CustomProperties: map[string]interface{}{
+				"namespace": "{{ params.namespace }}",
+				"container": map[string]interface{}{"tag": "{{ params.track }}"},
+				"hosts":     []interface{}{"{{ params.namespace }}.ziplinee.io"},
+			},
+			Services:       []*ZiplineeService{{Name: "database", ContainerImage: "cockroachdb/cockroach:{{ params.track }}"}},
+			ParallelStages: []*ZiplineeStage{{Name: "notify", When: "status == '{{ params.status }}'"}},
+		}
+
+		// act
+		stage.replaceTemplateParameters(map[string]string{"namespace": "ziplinee", "track": "stable", "status": "failed"})
+
+		assert.Equal(t, "extensions/gke:stable", stage.ContainerImage)
+		assert.Equal(t, []string{"echo ziplinee"}, stage.Commands)
+		assert.Equal(t, "ziplinee", stage.EnvVars["NAMESPACE"])
+		assert.Equal(t, "ziplinee", stage.CustomProperties["namespace"])
+		assert.Equal(t, "stable", stage.CustomProperties["container"].(map[string]interface{})["tag"])
+		assert.Equal(t, "ziplinee.ziplinee.io", stage.CustomProperties["hosts"].([]interface{})[0])
+		assert.Equal(t, "cockroachdb/cockroach:stable", stage.Services[0].ContainerImage)
+		assert.Equal(t, "status == 'failed'", stage.ParallelStages[0].When)
+	})
+}
+
+func TestReadManifestWithParameterizedReleaseTemplates(t *testing.T) {
+
+	template := `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    parameters:
+    - name: namespace
+      required: true
+    - name: replicas
+      default: "2"
+    stages:
+      deploy:
+        image: extensions/gke:stable
+        namespace: '{{ params.namespace }}'
+        replicas: '{{ params.replicas }}'
+        env:
+          NAMESPACE: '{{ params.namespace }}'
+`
+
+	t.Run("ReplacesPlaceholdersWithTemplateParamsAndDefaults", func(t *testing.T) {
+
+		// act
+		manifest, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  staging:
+    template: kubernetes
+    templateParams:
+      namespace: staging
+  production:
+    template: kubernetes
+    templateParams:
+      namespace: production
+      replicas: "5"`, true)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(manifest.Releases)) {
+			assert.Equal(t, "staging", manifest.Releases[0].Stages[0].CustomProperties["namespace"])
+			assert.Equal(t, "2", manifest.Releases[0].Stages[0].CustomProperties["replicas"])
+			assert.Equal(t, "staging", manifest.Releases[0].Stages[0].EnvVars["NAMESPACE"])
+			assert.Equal(t, "production", manifest.Releases[1].Stages[0].CustomProperties["namespace"])
+			assert.Equal(t, "5", manifest.Releases[1].Stages[0].CustomProperties["replicas"])
+		}
+		// the template itself keeps its placeholders
+		assert.Equal(t, "{{ params.namespace }}", manifest.ReleaseTemplates[0].Stages[0].CustomProperties["namespace"])
+	})
+
+	t.Run("ReturnsErrorForMissingRequiredParameter", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  staging:
+    template: kubernetes`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, ValidationCodeTemplateInvalid, result.Errors()[0].Code)
+			assert.Equal(t, "releases.staging.templateParams", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForUnknownParameter", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  staging:
+    template: kubernetes
+    templateParams:
+      namespace: staging
+      cluster: production`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "releases.staging.templateParams.cluster", result.Errors()[0].Path)
+		}
+	})
+
+	t.Run("ReturnsErrorForTemplateParamsWithoutTemplate", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), template+`
+releases:
+  staging:
+    templateParams:
+      namespace: staging
+    stages:
+      deploy:
+        image: extensions/gke:stable`, true)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForPlaceholderOfUndeclaredParameter", func(t *testing.T) {
+
+		// act
+		_, err := ReadManifest(GetDefaultManifestPreferences(), `
+stages:
+  build:
+    image: golang
+releaseTemplates:
+  kubernetes:
+    stages:
+      deploy:
+        image: extensions/gke:stable
+        namespace: '{{ params.namespace }}'`, true)
+
+		var result *ValidationResult
+		if assert.True(t, errors.As(err, &result)) && assert.Equal(t, 1, len(result.Errors())) {
+			assert.Equal(t, "releaseTemplates.kubernetes.stages.deploy", result.Errors()[0].Path)
+		}
+	})
+}
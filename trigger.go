@@ -2,7 +2,9 @@ package manifest
 
 import (
 	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,6 +22,13 @@ type ZiplineeTrigger struct {
 	PubSub    *ZiplineePubSubTrigger    `yaml:"pubsub,omitempty" json:"pubsub,omitempty"`
 	Github    *ZiplineeGithubTrigger    `yaml:"github,omitempty" json:"github,omitempty"`
 	Bitbucket *ZiplineeBitbucketTrigger `yaml:"bitbucket,omitempty" json:"bitbucket,omitempty"`
+	Gitlab    *ZiplineeGitlabTrigger    `yaml:"gitlab,omitempty" json:"gitlab,omitempty"`
+	Webhook   *ZiplineeWebhookTrigger   `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Manual    *ZiplineeManualTrigger    `yaml:"manual,omitempty" json:"manual,omitempty"`
+	All       *ZiplineeCompositeTrigger `yaml:"all,omitempty" json:"all,omitempty"`
+	Any       *ZiplineeCompositeTrigger `yaml:"any,omitempty" json:"any,omitempty"`
+
+	Concurrency *ZiplineeConcurrency `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
 
 	BuildAction   *ZiplineeTriggerBuildAction   `yaml:"builds,omitempty" json:"builds,omitempty"`
 	ReleaseAction *ZiplineeTriggerReleaseAction `yaml:"releases,omitempty" json:"releases,omitempty"`
@@ -32,6 +41,10 @@ type ZiplineePipelineTrigger struct {
 	Status string `yaml:"status,omitempty" json:"status,omitempty"`
 	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
 	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	// Version is a semver range like >=2.0.0 <3.0.0, regexes need an =~ or !~ prefix; see versionConstraintMatch
+	Version     string   `yaml:"version,omitempty" json:"version,omitempty"`
+	Paths       []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	PathsIgnore []string `yaml:"pathsIgnore,omitempty" json:"pathsIgnore,omitempty"`
 }
 
 // ZiplineeReleaseTrigger fires for pipeline releases and applies filtering to limit when this results in an action
@@ -40,6 +53,8 @@ type ZiplineeReleaseTrigger struct {
 	Status string `yaml:"status,omitempty" json:"status,omitempty"`
 	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
 	Target string `yaml:"target,omitempty" json:"target,omitempty"`
+	// Version is matched like the version of pipeline triggers
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 }
 
 // ZiplineeGitTrigger fires for git repository changes and applies filtering to limit when this results in an action
@@ -47,6 +62,12 @@ type ZiplineeGitTrigger struct {
 	Event      string `yaml:"event,omitempty" json:"event,omitempty"`
 	Repository string `yaml:"repository,omitempty" json:"repository,omitempty"`
 	Branch     string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	// Tag is an anchored regex like the other matching fields, not a glob; validation rejects globs like v*, use v.* instead
+	Tag          string   `yaml:"tag,omitempty" json:"tag,omitempty"`
+	SourceBranch string   `yaml:"sourceBranch,omitempty" json:"sourceBranch,omitempty"`
+	TargetBranch string   `yaml:"targetBranch,omitempty" json:"targetBranch,omitempty"`
+	Paths        []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	PathsIgnore  []string `yaml:"pathsIgnore,omitempty" json:"pathsIgnore,omitempty"`
 }
 
 // ZiplineeDockerTrigger fires for docker image changes and applies filtering to limit when this results in an action
@@ -58,25 +79,50 @@ type ZiplineeDockerTrigger struct {
 
 // ZiplineePubSubTrigger fires for pubsub events in a certain project and topic
 type ZiplineePubSubTrigger struct {
-	Project string `yaml:"project,omitempty" json:"project,omitempty"`
-	Topic   string `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Project string                   `yaml:"project,omitempty" json:"project,omitempty"`
+	Topic   string                   `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Filters []*ZiplineeTriggerFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
 }
 
 // ZiplineeGithubTrigger fires for github events
 type ZiplineeGithubTrigger struct {
-	Events     []string `yaml:"events,omitempty" json:"events,omitempty"`
-	Repository string   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Events     []string                 `yaml:"events,omitempty" json:"events,omitempty"`
+	Repository string                   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Filters    []*ZiplineeTriggerFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
 }
 
 // ZiplineeBitbucketTrigger fires for bitbucket events
 type ZiplineeBitbucketTrigger struct {
-	Events     []string `yaml:"events,omitempty" json:"events,omitempty"`
-	Repository string   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Events     []string                 `yaml:"events,omitempty" json:"events,omitempty"`
+	Repository string                   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Filters    []*ZiplineeTriggerFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
+}
+
+// ZiplineeGitlabTrigger fires for gitlab events
+type ZiplineeGitlabTrigger struct {
+	Events     []string                 `yaml:"events,omitempty" json:"events,omitempty"`
+	Repository string                   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Filters    []*ZiplineeTriggerFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
+}
+
+var webhookNameRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// ZiplineeWebhookTrigger fires for generic http webhooks posted to the endpoint with the trigger's name
+type ZiplineeWebhookTrigger struct {
+	Name    string                   `yaml:"name,omitempty" json:"name,omitempty"`
+	Headers map[string]string        `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Filters []*ZiplineeTriggerFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
+}
+
+// ZiplineeManualTrigger fires when a user manually starts a build, release or bot run
+type ZiplineeManualTrigger struct {
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
 }
 
 // ZiplineeCronTrigger fires at intervals specified by the cron schedule
 type ZiplineeCronTrigger struct {
 	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
 }
 
 // ZiplineeTriggerBuildAction determines what builds when the trigger fires
@@ -96,6 +142,17 @@ type ZiplineeTriggerBotAction struct {
 	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
 }
 
+// ZiplineeTriggerMatch is a trigger that fired for an event, together with the action to take
+type ZiplineeTriggerMatch struct {
+	Type          TriggerType
+	Trigger       *ZiplineeTrigger
+	BuildAction   *ZiplineeTriggerBuildAction
+	ReleaseAction *ZiplineeTriggerReleaseAction
+	BotAction     *ZiplineeTriggerBotAction
+	// Concurrency is the policy to enforce for the run the trigger starts, or nil if the trigger and its release or bot have none
+	Concurrency *ZiplineeResolvedConcurrency
+}
+
 // SetDefaults sets defaults for ZiplineeTrigger
 func (t *ZiplineeTrigger) SetDefaults(preferences ZiplineeManifestPreferences, triggerType TriggerType, targetName string) {
 	if t.Pipeline != nil {
@@ -122,6 +179,24 @@ func (t *ZiplineeTrigger) SetDefaults(preferences ZiplineeManifestPreferences, t
 	if t.Bitbucket != nil {
 		t.Bitbucket.SetDefaults()
 	}
+	if t.Gitlab != nil {
+		t.Gitlab.SetDefaults()
+	}
+	if t.Webhook != nil {
+		t.Webhook.SetDefaults()
+	}
+	if t.Manual != nil {
+		t.Manual.SetDefaults()
+	}
+	if t.All != nil {
+		t.All.SetDefaults(preferences)
+	}
+	if t.Any != nil {
+		t.Any.SetDefaults(preferences)
+	}
+	if t.Concurrency != nil {
+		t.Concurrency.SetDefaults()
+	}
 
 	switch triggerType {
 	case TriggerTypeBuild:
@@ -170,13 +245,16 @@ func (g *ZiplineeGitTrigger) SetDefaults() {
 	if g.Event == "" {
 		g.Event = "push"
 	}
-	if g.Branch == "" {
+	if g.Event == "push" && g.Branch == "" {
 		g.Branch = "master|main"
 	}
 }
 
 // SetDefaults sets defaults for ZiplineeDockerTrigger
 func (d *ZiplineeDockerTrigger) SetDefaults() {
+	if d.Event == "" {
+		d.Event = "push"
+	}
 }
 
 // SetDefaults sets defaults for ZiplineeCronTrigger
@@ -201,6 +279,21 @@ func (p *ZiplineeBitbucketTrigger) SetDefaults() {
 	}
 }
 
+// SetDefaults sets defaults for ZiplineeGitlabTrigger
+func (p *ZiplineeGitlabTrigger) SetDefaults() {
+	if p.Repository == "" {
+		p.Repository = "self"
+	}
+}
+
+// SetDefaults sets defaults for ZiplineeWebhookTrigger
+func (w *ZiplineeWebhookTrigger) SetDefaults() {
+}
+
+// SetDefaults sets defaults for ZiplineeManualTrigger
+func (m *ZiplineeManualTrigger) SetDefaults() {
+}
+
 // SetDefaults sets defaults for ZiplineeTriggerBuildAction
 func (b *ZiplineeTriggerBuildAction) SetDefaults(preferences ZiplineeManifestPreferences) {
 	if b.Branch == "" {
@@ -212,12 +305,13 @@ func (b *ZiplineeTriggerBuildAction) SetDefaults(preferences ZiplineeManifestPre
 func (r *ZiplineeTriggerReleaseAction) SetDefaults(t *ZiplineeTrigger, targetName string) {
 	r.Target = targetName
 	if r.Version == "" {
-		if t.Pipeline != nil && t.Pipeline.Name == "self" {
-			r.Version = "same"
-		} else if t.Release != nil && t.Release.Name == "self" {
-			r.Version = "same"
-		} else {
-			r.Version = "latest"
+		r.Version = "latest"
+		for _, leaf := range t.leafTriggers() {
+			if leaf.Pipeline != nil && leaf.Pipeline.Name == "self" {
+				r.Version = "same"
+			} else if leaf.Release != nil && leaf.Release.Name == "self" {
+				r.Version = "same"
+			}
 		}
 	}
 }
@@ -242,69 +336,116 @@ func (t *ZiplineeTrigger) Validate(triggerType TriggerType, targetName string) (
 		t.Cron == nil &&
 		t.PubSub == nil &&
 		t.Github == nil &&
-		t.Bitbucket == nil {
-		return fmt.Errorf("Set at least a 'pipeline', 'release', 'git', 'docker', 'cron', 'pubsub', 'github' or 'bitbucket' trigger")
+		t.Bitbucket == nil &&
+		t.Gitlab == nil &&
+		t.Webhook == nil &&
+		t.Manual == nil &&
+		t.All == nil &&
+		t.Any == nil {
+		return fmt.Errorf("Set at least a 'pipeline', 'release', 'git', 'docker', 'cron', 'pubsub', 'github', 'bitbucket', 'gitlab', 'webhook', 'manual', 'all' or 'any' trigger")
 	}
 
 	if t.Pipeline != nil {
 		err = t.Pipeline.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("pipeline", err)
 		}
 		numberOfTypes++
 	}
 	if t.Release != nil {
 		err = t.Release.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("release", err)
 		}
 		numberOfTypes++
 	}
 	if t.Git != nil {
 		err = t.Git.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("git", err)
 		}
 		numberOfTypes++
 	}
 	if t.Docker != nil {
 		err = t.Docker.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("docker", err)
 		}
 		numberOfTypes++
 	}
 	if t.Cron != nil {
 		err = t.Cron.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("cron", err)
 		}
 		numberOfTypes++
 	}
 	if t.PubSub != nil {
 		err = t.PubSub.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("pubsub", err)
 		}
 		numberOfTypes++
 	}
 	if t.Github != nil {
 		err = t.Github.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("github", err)
 		}
 		numberOfTypes++
 	}
 	if t.Bitbucket != nil {
 		err = t.Bitbucket.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("bitbucket", err)
+		}
+		numberOfTypes++
+	}
+	if t.Gitlab != nil {
+		err = t.Gitlab.Validate()
+		if err != nil {
+			return wrapManifestError("gitlab", err)
+		}
+		numberOfTypes++
+	}
+	if t.Webhook != nil {
+		err = t.Webhook.Validate()
+		if err != nil {
+			return wrapManifestError("webhook", err)
+		}
+		numberOfTypes++
+	}
+	if t.Manual != nil {
+		err = t.Manual.Validate()
+		if err != nil {
+			return wrapManifestError("manual", err)
+		}
+		numberOfTypes++
+	}
+	if t.All != nil {
+		err = t.All.Validate("all")
+		if err != nil {
+			return wrapManifestError("all", err)
+		}
+		numberOfTypes++
+	}
+	if t.Any != nil {
+		err = t.Any.Validate("any")
+		if err != nil {
+			return wrapManifestError("any", err)
 		}
 		numberOfTypes++
 	}
 
 	if numberOfTypes != 1 {
-		return fmt.Errorf("Do not specify more than one type of trigger 'pipeline', 'release', 'git', 'docker', 'cron', 'pubsub', 'github' or 'bitbucket' per trigger object")
+		return fmt.Errorf("Do not specify more than one type of trigger 'pipeline', 'release', 'git', 'docker', 'cron', 'pubsub', 'github', 'bitbucket', 'gitlab', 'webhook', 'manual', 'all' or 'any' per trigger object")
+	}
+
+	if t.Concurrency != nil {
+		err = t.Concurrency.Validate()
+		if err != nil {
+			return wrapManifestError("concurrency", err)
+		}
 	}
 
 	switch triggerType {
@@ -317,7 +458,7 @@ func (t *ZiplineeTrigger) Validate(triggerType TriggerType, targetName string) (
 		}
 		err = t.BuildAction.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("builds", err)
 		}
 	case TriggerTypeRelease:
 		if t.ReleaseAction == nil {
@@ -328,7 +469,7 @@ func (t *ZiplineeTrigger) Validate(triggerType TriggerType, targetName string) (
 		}
 		err = t.ReleaseAction.Validate(targetName)
 		if err != nil {
-			return err
+			return wrapManifestError("releases", err)
 		}
 	case TriggerTypeBot:
 		if t.BotAction == nil {
@@ -336,7 +477,7 @@ func (t *ZiplineeTrigger) Validate(triggerType TriggerType, targetName string) (
 		}
 		err = t.BotAction.Validate()
 		if err != nil {
-			return err
+			return wrapManifestError("runs", err)
 		}
 	}
 
@@ -354,7 +495,10 @@ func (p *ZiplineePipelineTrigger) Validate() (err error) {
 	if p.Name == "" {
 		return fmt.Errorf("Set pipeline.name in your trigger to 'self' or a full qualified pipeline name, i.e. github.com/ziplineeci/ziplinee-ci-manifest")
 	}
-	return nil
+	if err := validateVersionConstraint(p.Version); err != nil {
+		return fmt.Errorf("Invalid pipeline.version in your trigger: %v", err)
+	}
+	return validatePathFilters("pipeline", p.Paths, p.PathsIgnore)
 }
 
 // Validate checks if ZiplineeReleaseTrigger is valid
@@ -371,22 +515,55 @@ func (r *ZiplineeReleaseTrigger) Validate() (err error) {
 	if r.Target == "" {
 		return fmt.Errorf("Set release.target in your trigger to a release target name on the pipeline set by release.name")
 	}
+	if err := validateVersionConstraint(r.Version); err != nil {
+		return fmt.Errorf("Invalid release.version in your trigger: %v", err)
+	}
 	return nil
 }
 
 // Validate checks if ZiplineeGitTrigger is valid
 func (g *ZiplineeGitTrigger) Validate() (err error) {
-	if g.Event != "push" {
-		return fmt.Errorf("Set git.event in your trigger to 'push'")
+	if g.Event != "push" && g.Event != "tag" && g.Event != "pull_request" {
+		return fmt.Errorf("Set git.event in your trigger to 'push', 'tag' or 'pull_request'")
 	}
 	if g.Repository == "" {
 		return fmt.Errorf("Set git.repository in your trigger to a full qualified git repository name, i.e. github.com/ziplineeci/ziplinee-ci-manifest")
 	}
-	return nil
+	if g.Event != "push" && g.Branch != "" {
+		return fmt.Errorf("Only set git.branch in your trigger for event 'push', use git.sourceBranch and git.targetBranch for event 'pull_request'")
+	}
+	if g.Event != "tag" && g.Tag != "" {
+		return fmt.Errorf("Only set git.tag in your trigger for event 'tag'")
+	}
+	if g.Event != "pull_request" && (g.SourceBranch != "" || g.TargetBranch != "") {
+		return fmt.Errorf("Only set git.sourceBranch and git.targetBranch in your trigger for event 'pull_request'")
+	}
+	if _, err := regexMatch(g.Branch, ""); err != nil {
+		return fmt.Errorf("Invalid git.branch in your trigger: %v", err)
+	}
+	if _, err := regexMatch(g.Tag, ""); err != nil {
+		return fmt.Errorf("Invalid git.tag in your trigger, set it to a regex like v.* to match tags starting with v: %v", err)
+	}
+	if isGlobLikeRegex(g.Tag) {
+		return fmt.Errorf("Invalid git.tag %v in your trigger, it's a regex and not a glob, so use .* instead of * like v.* to match tags starting with v", g.Tag)
+	}
+	if _, err := regexMatch(g.SourceBranch, ""); err != nil {
+		return fmt.Errorf("Invalid git.sourceBranch in your trigger: %v", err)
+	}
+	if _, err := regexMatch(g.TargetBranch, ""); err != nil {
+		return fmt.Errorf("Invalid git.targetBranch in your trigger: %v", err)
+	}
+	return validatePathFilters("git", g.Paths, g.PathsIgnore)
 }
 
 // Validate checks if ZiplineeDockerTrigger is valid
 func (d *ZiplineeDockerTrigger) Validate() (err error) {
+	if d.Event == "" {
+		return fmt.Errorf("Set docker.event in your trigger to 'push'")
+	}
+	if d.Image == "" {
+		return fmt.Errorf("Set docker.image in your trigger to a full qualified image name, i.e. ziplinee/ziplinee-ci-api")
+	}
 	return nil
 }
 
@@ -396,10 +573,18 @@ func (c *ZiplineeCronTrigger) Validate() (err error) {
 	if c.Schedule == "" {
 		return fmt.Errorf("Set cron.schedule in your trigger to '<minute> <hour> <day of month> <month> <day of week>'")
 	}
-	_, err = cron.ParseStandard(c.Schedule)
+	schedule, err := resolveHashedCronSchedule(c.Schedule, "")
 	if err != nil {
 		return fmt.Errorf("Invalid cron.schedule in your trigger: %v", err)
 	}
+	_, err = cron.ParseStandard(schedule)
+	if err != nil {
+		return fmt.Errorf("Invalid cron.schedule in your trigger: %v", err)
+	}
+	_, err = loadCronLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("Invalid cron.timezone in your trigger, set it to an IANA timezone name like Europe/Amsterdam: %v", err)
+	}
 
 	return nil
 }
@@ -413,7 +598,7 @@ func (p *ZiplineePubSubTrigger) Validate() (err error) {
 		return fmt.Errorf("Set pubsub.topic in your trigger to the pubsub topic you want this pipeline to subscribe to")
 	}
 
-	return nil
+	return validateFilters("pubsub", p.Filters, true)
 }
 
 // Validate checks if ZiplineeGithubTrigger is valid
@@ -421,8 +606,13 @@ func (p *ZiplineeGithubTrigger) Validate() (err error) {
 	if len(p.Events) == 0 {
 		return fmt.Errorf("Set array github.events in your trigger to at least one github event")
 	}
+	for _, ev := range p.Events {
+		if _, err := eventMatch(ev, ""); err != nil {
+			return fmt.Errorf("Invalid github.events entry %v in your trigger: %v", ev, err)
+		}
+	}
 
-	return nil
+	return validateFilters("github", p.Filters, false)
 }
 
 // Validate checks if ZiplineeBitbucketTrigger is valid
@@ -430,6 +620,58 @@ func (p *ZiplineeBitbucketTrigger) Validate() (err error) {
 	if len(p.Events) == 0 {
 		return fmt.Errorf("Set array bitbucket.events in your trigger to at least one bitbucket event")
 	}
+	for _, ev := range p.Events {
+		if _, err := eventMatch(ev, ""); err != nil {
+			return fmt.Errorf("Invalid bitbucket.events entry %v in your trigger: %v", ev, err)
+		}
+	}
+
+	return validateFilters("bitbucket", p.Filters, false)
+}
+
+// Validate checks if ZiplineeGitlabTrigger is valid
+func (p *ZiplineeGitlabTrigger) Validate() (err error) {
+	if len(p.Events) == 0 {
+		return fmt.Errorf("Set array gitlab.events in your trigger to at least one gitlab event")
+	}
+	for _, ev := range p.Events {
+		if _, err := eventMatch(ev, ""); err != nil {
+			return fmt.Errorf("Invalid gitlab.events entry %v in your trigger: %v", ev, err)
+		}
+	}
+
+	return validateFilters("gitlab", p.Filters, false)
+}
+
+// Validate checks if ZiplineeWebhookTrigger is valid
+func (w *ZiplineeWebhookTrigger) Validate() (err error) {
+	if w.Name == "" {
+		return fmt.Errorf("Set webhook.name in your trigger to the name of the webhook endpoint the events are posted to")
+	}
+	if !webhookNameRegex.MatchString(w.Name) {
+		return fmt.Errorf("Invalid webhook.name %v in your trigger, only use letters, digits, dots, dashes and underscores", w.Name)
+	}
+	headers := make([]string, 0, len(w.Headers))
+	for header := range w.Headers {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		if _, err := regexMatch(w.Headers[header], ""); err != nil {
+			return fmt.Errorf("Invalid webhook.headers pattern %v for header %v in your trigger: %v", w.Headers[header], header, err)
+		}
+	}
+
+	return validateFilters("webhook", w.Filters, false)
+}
+
+// Validate checks if ZiplineeManualTrigger is valid
+func (m *ZiplineeManualTrigger) Validate() (err error) {
+	if m.User != "" {
+		if _, err := regexMatch(m.User, ""); err != nil {
+			return fmt.Errorf("Invalid manual.user in your trigger: %v", err)
+		}
+	}
 
 	return nil
 }
@@ -453,8 +695,13 @@ func (b *ZiplineeTriggerBotAction) Validate() (err error) {
 	return nil
 }
 
-// ReplaceSelf replaces pipeline names set to "self" with the actual pipeline name
+// ReplaceSelf replaces pipeline names set to "self" with the actual pipeline name and resolves hashed H tokens in cron schedules for the pipeline
 func (t *ZiplineeTrigger) ReplaceSelf(pipeline string) {
+	if t.Cron != nil {
+		if schedule, err := resolveHashedCronSchedule(t.Cron.Schedule, pipeline); err == nil {
+			t.Cron.Schedule = schedule
+		}
+	}
 	if t.Pipeline != nil && t.Pipeline.Name == "self" {
 		t.Pipeline.Name = pipeline
 	}
@@ -467,6 +714,51 @@ func (t *ZiplineeTrigger) ReplaceSelf(pipeline string) {
 	if t.Bitbucket != nil && t.Bitbucket.Repository == "self" {
 		t.Bitbucket.Repository = pipeline
 	}
+	if t.Gitlab != nil && t.Gitlab.Repository == "self" {
+		t.Gitlab.Repository = pipeline
+	}
+	for _, leaf := range t.leafTriggers() {
+		if leaf != t {
+			leaf.ReplaceSelf(pipeline)
+		}
+	}
+}
+
+// Fires indicates whether ZiplineeTrigger fires for an ZiplineeEvent, by checking the trigger type matching the populated event type;
+// it has no events before it, so an 'all' trigger only fires if the event fires all its sub-triggers, use FiresForEvents to pass them
+func (t *ZiplineeTrigger) Fires(e *ZiplineeEvent) bool {
+	if e == nil {
+		return false
+	}
+
+	switch {
+	case t.All != nil || t.Any != nil:
+		return t.FiresForEvents([]*ZiplineeEvent{e})
+	case t.Pipeline != nil && e.Pipeline != nil:
+		return t.Pipeline.Fires(e.Pipeline)
+	case t.Release != nil && e.Release != nil:
+		return t.Release.Fires(e.Release)
+	case t.Git != nil && e.Git != nil:
+		return t.Git.Fires(e.Git)
+	case t.Docker != nil && e.Docker != nil:
+		return t.Docker.Fires(e.Docker)
+	case t.Cron != nil && e.Cron != nil:
+		return t.Cron.Fires(e.Cron)
+	case t.PubSub != nil && e.PubSub != nil:
+		return t.PubSub.Fires(e.PubSub)
+	case t.Github != nil && e.Github != nil:
+		return t.Github.Fires(e.Github)
+	case t.Bitbucket != nil && e.Bitbucket != nil:
+		return t.Bitbucket.Fires(e.Bitbucket)
+	case t.Gitlab != nil && e.Gitlab != nil:
+		return t.Gitlab.Fires(e.Gitlab)
+	case t.Webhook != nil && e.Webhook != nil:
+		return t.Webhook.Fires(e.Webhook)
+	case t.Manual != nil && e.Manual != nil:
+		return t.Manual.Fires(e.Manual)
+	}
+
+	return false
 }
 
 // Fires indicates whether ZiplineePipelineTrigger fires for an ZiplineePipelineEvent
@@ -498,7 +790,14 @@ func (p *ZiplineePipelineTrigger) Fires(e *ZiplineePipelineEvent) bool {
 		return false
 	}
 
-	return true
+	// compare build version against regex or semver range
+	versionMatched, err := versionConstraintMatch(p.Version, e.BuildVersion)
+	if err != nil || !versionMatched {
+		return false
+	}
+
+	// check changed files against paths and pathsIgnore globs
+	return changedFilesMatch(p.Paths, p.PathsIgnore, e.ChangedFiles)
 }
 
 // Fires indicates whether ZiplineeReleaseTrigger fires for an ZiplineeReleaseEvent
@@ -529,6 +828,12 @@ func (r *ZiplineeReleaseTrigger) Fires(e *ZiplineeReleaseEvent) bool {
 		return false
 	}
 
+	// compare release version against regex or semver range
+	versionMatched, err := versionConstraintMatch(r.Version, e.ReleaseVersion)
+	if err != nil || !versionMatched {
+		return false
+	}
+
 	return true
 }
 
@@ -546,41 +851,90 @@ func (g *ZiplineeGitTrigger) Fires(e *ZiplineeGitEvent) bool {
 		return false
 	}
 
-	// compare branch as regex
-	branchMatched, err := regexMatch(g.Branch, e.Branch)
-	if err != nil || !branchMatched {
-		return false
+	switch e.Event {
+	case "tag":
+		// compare tag as regex, any tag matches if not set
+		if !optionalRegexMatch(g.Tag, e.Tag) {
+			return false
+		}
+	case "pull_request":
+		// compare source and target branch as regex, any branch matches if not set
+		if !optionalRegexMatch(g.SourceBranch, e.SourceBranch) || !optionalRegexMatch(g.TargetBranch, e.TargetBranch) {
+			return false
+		}
+	default:
+		// compare branch as regex
+		branchMatched, err := regexMatch(g.Branch, e.Branch)
+		if err != nil || !branchMatched {
+			return false
+		}
 	}
 
-	return true
+	// check changed files against paths and pathsIgnore globs
+	return changedFilesMatch(g.Paths, g.PathsIgnore, e.ChangedFiles)
 }
 
 // Fires indicates whether ZiplineeDockerTrigger fires for an ZiplineeDockerEvent
 func (d *ZiplineeDockerTrigger) Fires(e *ZiplineeDockerEvent) bool {
-	return false
+	// compare event as regex
+	eventMatched, err := regexMatch(d.Event, e.Event)
+	if err != nil || !eventMatched {
+		return false
+	}
+
+	// compare image as regex
+	imageMatched, err := regexMatch(d.Image, e.Image)
+	if err != nil || !imageMatched {
+		return false
+	}
+
+	// compare tag as regex, any tag matches if not set
+	if d.Tag != "" {
+		tagMatched, err := regexMatch(d.Tag, e.Tag)
+		if err != nil || !tagMatched {
+			return false
+		}
+	}
+
+	return true
 }
 
-// Fires indicates whether ZiplineeCronTrigger fires for an ZiplineeCronEvent
+// Fires indicates whether ZiplineeCronTrigger fires for an ZiplineeCronEvent, evaluating the schedule in the trigger's timezone
 func (c *ZiplineeCronTrigger) Fires(e *ZiplineeCronEvent) bool {
 
+	// truncate event time to the minute
+	eventTime := e.Time.Truncate(time.Minute)
+
+	// get the first time the cron expression fires since the previous minute
+	nextTimes, err := c.NextFireTimes(eventTime.Add(time.Minute*-1), 1)
+	if err != nil || len(nextTimes) == 0 {
+		return false
+	}
+
+	return nextTimes[0].Equal(eventTime)
+}
+
+// NextFireTimes returns the next n times after from at which ZiplineeCronTrigger fires, in the trigger's timezone
+func (c *ZiplineeCronTrigger) NextFireTimes(from time.Time, n int) (times []time.Time, err error) {
+
+	// H tokens are resolved by ReplaceSelf, any left at this point are resolved without pipeline name
+	schedule, err := resolveHashedCronSchedule(c.Schedule, "")
+	if err != nil {
+		return nil, err
+	}
+
 	// ParseStandard expects 5 entries representing: minute, hour, day of month, month and day of week, in that order.
-	sched, err := cron.ParseStandard(c.Schedule)
+	sched, err := cron.ParseStandard(schedule)
 	if err != nil {
-		return false
+		return nil, err
 	}
 
-	// truncate event time to the minute
-	eventTime := time.Date(e.Time.Year(), e.Time.Month(), e.Time.Day(), e.Time.Hour(), e.Time.Minute(), 0, 0, time.UTC)
-	// subtract 1 minute, otherwise the next time is at least 1 minute later
-	eventTime = eventTime.Add(time.Minute * -1)
-	// get the next time the cron expression would fire
-	nextTime := sched.Next(eventTime)
+	location, err := loadCronLocation(c.Timezone)
+	if err != nil {
+		return nil, err
+	}
 
-	return nextTime.Year() == e.Time.Year() &&
-		nextTime.Month() == e.Time.Month() &&
-		nextTime.Day() == e.Time.Day() &&
-		nextTime.Hour() == e.Time.Hour() &&
-		nextTime.Minute() == e.Time.Minute()
+	return cronNextTimes(sched, location, from, n), nil
 }
 
 func regexMatch(pattern, value string) (bool, error) {
@@ -598,9 +952,9 @@ func regexMatch(pattern, value string) (bool, error) {
 		pattern = strings.TrimPrefix(pattern, "!~")
 	}
 
-	pattern = fmt.Sprintf("^(%v)$", strings.TrimSpace(pattern))
+	pattern = "^(" + strings.TrimSpace(pattern) + ")$"
 
-	match, err := regexp.MatchString(pattern, value)
+	match, err := compiledRegexes.MatchString(pattern, value)
 
 	if err != nil {
 		return false, err
@@ -613,48 +967,174 @@ func regexMatch(pattern, value string) (bool, error) {
 	return match, nil
 }
 
+// optionalRegexMatch matches the value against the pattern as regex, with an empty pattern matching any value
+func optionalRegexMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	match, err := regexMatch(pattern, value)
+
+	return err == nil && match
+}
+
+// eventsMatch indicates whether any of the event patterns matches the event
+func eventsMatch(patterns []string, event string) bool {
+	for _, pattern := range patterns {
+		if match, err := eventMatch(pattern, event); err == nil && match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventMatch matches an event against a glob pattern like pullrequest:*, or against a regex if the pattern starts with =~ or !~
+func eventMatch(pattern, event string) (bool, error) {
+	if strings.HasPrefix(pattern, "=~") || strings.HasPrefix(pattern, "!~") {
+		return regexMatch(pattern, event)
+	}
+
+	return path.Match(strings.TrimSpace(pattern), event)
+}
+
+// isGlobLikeRegex indicates whether the regex uses * like a glob, i.e. v* that only matches v, vv and so on instead of any tag
+// starting with v; a * following ., a group, a class, a repetition or an escaped character is used as regex
+func isGlobLikeRegex(pattern string) bool {
+	repeatable := false
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			// escaped characters like \d or \. can be repeated
+			i++
+			repeatable = true
+		case '[':
+			// skip the class, a * in it is literal
+			end := strings.Index(pattern[i+1:], "]")
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+			repeatable = true
+		case '.', ')', '}':
+			repeatable = true
+		case '*':
+			if !repeatable && i > 0 {
+				return true
+			}
+			repeatable = false
+		default:
+			repeatable = false
+		}
+	}
+
+	return false
+}
+
 // Fires indicates whether ZiplineePubSubTrigger fires for an ZiplineePubSubEvent
 func (p *ZiplineePubSubTrigger) Fires(e *ZiplineePubSubEvent) bool {
 
-	projectMatch, err := regexp.MatchString(fmt.Sprintf("^(%v)$", strings.TrimSpace(p.Project)), e.Project)
+	projectMatch, err := compiledRegexes.MatchString("^("+strings.TrimSpace(p.Project)+")$", e.Project)
 	if !projectMatch || err != nil {
 		return false
 	}
 
-	topicMatch, err := regexp.MatchString(fmt.Sprintf("^(%v)$", strings.TrimSpace(p.Topic)), e.Topic)
+	topicMatch, err := compiledRegexes.MatchString("^("+strings.TrimSpace(p.Topic)+")$", e.Topic)
 	if !topicMatch || err != nil {
 		return false
 	}
 
-	return true
+	return filtersMatch(p.Filters, pubsubMessagePayload(e.Message), e.Message.Attributes)
 }
 
 // Fires indicates whether ZiplineeGithubTrigger fires for an ZiplineeGithubEvent
 func (p *ZiplineeGithubTrigger) Fires(e *ZiplineeGithubEvent) bool {
-	if e.Repository != "" && e.Repository != p.Repository {
+	// compare repository case insensitive, events without repository are not filtered
+	if e.Repository != "" && !strings.EqualFold(p.Repository, e.Repository) {
 		return false
 	}
 
-	for _, ev := range p.Events {
-		if ev == e.Event {
-			return true
-		}
+	if !eventsMatch(p.Events, e.Event) {
+		return false
 	}
 
-	return true
+	return filtersMatch(p.Filters, e.Payload, nil)
 }
 
 // Fires indicates whether ZiplineeBitbucketTrigger fires for an ZiplineeBitbucketEvent
 func (p *ZiplineeBitbucketTrigger) Fires(e *ZiplineeBitbucketEvent) bool {
-	if e.Repository != "" && e.Repository != p.Repository {
+	// compare repository case insensitive, events without repository are not filtered
+	if e.Repository != "" && !strings.EqualFold(p.Repository, e.Repository) {
 		return false
 	}
 
-	for _, ev := range p.Events {
-		if ev == e.Event {
-			return true
+	if !eventsMatch(p.Events, e.Event) {
+		return false
+	}
+
+	return filtersMatch(p.Filters, e.Payload, nil)
+}
+
+// Fires indicates whether ZiplineeGitlabTrigger fires for an ZiplineeGitlabEvent
+func (p *ZiplineeGitlabTrigger) Fires(e *ZiplineeGitlabEvent) bool {
+	// compare repository case insensitive, events without repository are not filtered
+	if e.Repository != "" && !strings.EqualFold(p.Repository, e.Repository) {
+		return false
+	}
+
+	if !eventsMatch(p.Events, e.Event) {
+		return false
+	}
+
+	return filtersMatch(p.Filters, e.Payload, nil)
+}
+
+// Fires indicates whether ZiplineeWebhookTrigger fires for an ZiplineeWebhookEvent
+func (w *ZiplineeWebhookTrigger) Fires(e *ZiplineeWebhookEvent) bool {
+	// compare name case insensitive
+	if !strings.EqualFold(w.Name, e.Name) {
+		return false
+	}
+
+	// compare headers as regex, with header names being case insensitive like in http
+	for header, pattern := range w.Headers {
+		value, ok := headerValue(e.Headers, header)
+		if !ok {
+			return false
+		}
+		headerMatched, err := regexMatch(pattern, value)
+		if err != nil || !headerMatched {
+			return false
 		}
 	}
 
+	return filtersMatch(w.Filters, e.Payload, nil)
+}
+
+// headerValue returns the value for a header, looking up its name case insensitive
+func headerValue(headers map[string]string, name string) (value string, found bool) {
+	if value, found = headers[name]; found {
+		return
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// Fires indicates whether ZiplineeManualTrigger fires for an ZiplineeManualEvent
+func (m *ZiplineeManualTrigger) Fires(e *ZiplineeManualEvent) bool {
+	if m.User == "" {
+		return true
+	}
+
+	// compare user as regex
+	userMatched, err := regexMatch(m.User, e.UserID)
+	if err != nil || !userMatched {
+		return false
+	}
+
 	return true
 }
@@ -0,0 +1,183 @@
+package manifest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ZiplineeTriggerFilter limits when a trigger fires by matching a value from the event payload or pubsub message attributes against a regex
+type ZiplineeTriggerFilter struct {
+	Path      string `yaml:"path,omitempty" json:"path,omitempty"`
+	Attribute string `yaml:"attribute,omitempty" json:"attribute,omitempty"`
+	Pattern   string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+}
+
+// Validate checks if ZiplineeTriggerFilter is valid
+func (f *ZiplineeTriggerFilter) Validate() (err error) {
+	if f.Path == "" && f.Attribute == "" {
+		return fmt.Errorf("Set either path or attribute for filters in your trigger")
+	}
+	if f.Path != "" && f.Attribute != "" {
+		return fmt.Errorf("Do not set both path and attribute for a single filter in your trigger")
+	}
+	if f.Pattern == "" {
+		return fmt.Errorf("Set pattern for filters in your trigger to a regex the value should match")
+	}
+	if _, err := regexMatch(f.Pattern, ""); err != nil {
+		return fmt.Errorf("Invalid filter pattern %v in your trigger: %v", f.Pattern, err)
+	}
+
+	return nil
+}
+
+// Matches indicates whether the value at the filter's path in the json payload, or the filter's attribute, matches the pattern
+func (f *ZiplineeTriggerFilter) Matches(payload string, attributes map[string]string) bool {
+	return f.matches(newJSONPayload(payload), attributes)
+}
+
+func (f *ZiplineeTriggerFilter) matches(payload *jsonPayload, attributes map[string]string) bool {
+
+	var value string
+	if f.Attribute != "" {
+		v, ok := attributes[f.Attribute]
+		if !ok {
+			return false
+		}
+		value = v
+	} else {
+		v, ok := payload.value(f.Path)
+		if !ok {
+			return false
+		}
+		value = v
+	}
+
+	match, err := regexMatch(f.Pattern, value)
+
+	return err == nil && match
+}
+
+// filtersMatch indicates whether all filters match, which is true if there are no filters
+func filtersMatch(filters []*ZiplineeTriggerFilter, payload string, attributes map[string]string) bool {
+	// the payload is parsed once for all filters, when the first filter with a path needs it
+	document := newJSONPayload(payload)
+	for _, f := range filters {
+		if f != nil && !f.matches(document, attributes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateFilters checks all filters, with attributes only being available for pubsub triggers
+func validateFilters(triggerType string, filters []*ZiplineeTriggerFilter, allowAttributes bool) (err error) {
+	for i, f := range filters {
+		if f == nil {
+			continue
+		}
+		err = f.Validate()
+		if err == nil && f.Attribute != "" && !allowAttributes {
+			err = fmt.Errorf("Set path instead of attribute for %v.filters in your trigger, attributes are only supported for pubsub", triggerType)
+		}
+		if err != nil {
+			return wrapManifestError(fmt.Sprintf("filters[%v]", i), err)
+		}
+	}
+
+	return nil
+}
+
+// jsonPayload is a json document that's only parsed once a value is requested
+type jsonPayload struct {
+	raw      string
+	parsed   bool
+	valid    bool
+	document interface{}
+}
+
+func newJSONPayload(raw string) *jsonPayload {
+	return &jsonPayload{raw: raw}
+}
+
+// value returns the value at a path like comment.body or pull_request.labels[0].name from the json document
+func (p *jsonPayload) value(path string) (value string, found bool) {
+	if !p.parsed {
+		p.parsed = true
+		// keep numbers as they are in the payload, so large ids aren't turned into floats like 1e+06
+		decoder := json.NewDecoder(strings.NewReader(p.raw))
+		decoder.UseNumber()
+		p.valid = decoder.Decode(&p.document) == nil
+	}
+	if !p.valid {
+		return "", false
+	}
+
+	return jsonPathValue(p.document, path)
+}
+
+// jsonPathValue returns the value at a path like comment.body or pull_request.labels[0].name from a decoded json document
+func jsonPathValue(document interface{}, path string) (value string, found bool) {
+
+	current := document
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	for _, segment := range strings.Split(strings.ReplaceAll(path, "[", ".["), ".") {
+		if segment == "" {
+			continue
+		}
+
+		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+			array, ok := current.([]interface{})
+			if !ok {
+				return "", false
+			}
+			index, err := strconv.Atoi(segment[1 : len(segment)-1])
+			if err != nil || index < 0 || index >= len(array) {
+				return "", false
+			}
+			current = array[index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		current, ok = object[segment]
+		if !ok {
+			return "", false
+		}
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+
+	// objects and arrays are matched against their json representation
+	bytes, err := json.Marshal(current)
+	if err != nil {
+		return "", false
+	}
+
+	return string(bytes), true
+}
+
+// pubsubMessagePayload returns the message data, decoded from base64 if it was encoded for transport
+func pubsubMessagePayload(message PubsubMessage) string {
+	if decoded, err := base64.StdEncoding.DecodeString(message.Data); err == nil {
+		return string(decoded)
+	}
+
+	return message.Data
+}
@@ -0,0 +1,219 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZiplineeTriggerFilterValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfPathAndAttributeAreEmpty", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Pattern: "image-pushed",
+		}
+
+		// act
+		err := filter.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfPathAndAttributeAreBothSet", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:      "type",
+			Attribute: "type",
+			Pattern:   "image-pushed",
+		}
+
+		// act
+		err := filter.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfPatternIsInvalid", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "comment.body",
+			Pattern: "(/deploy",
+		}
+
+		// act
+		err := filter.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "comment.body",
+			Pattern: "(?s).*/deploy.*",
+		}
+
+		// act
+		err := filter.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeTriggerFilterMatches(t *testing.T) {
+
+	payload := `{"action":"created","comment":{"body":"looks good\n/deploy staging","id":12},"issue":{"labels":[{"name":"bug"},{"name":"deploy"}],"locked":false}}`
+
+	t.Run("ReturnsTrueIfValueAtPathMatchesPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "comment.body",
+			Pattern: "(?s).*/deploy.*",
+		}
+
+		// act
+		matches := filter.Matches(payload, nil)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsTrueIfValueAtArrayIndexMatchesPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "$.issue.labels[1].name",
+			Pattern: "deploy",
+		}
+
+		// act
+		matches := filter.Matches(payload, nil)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsTrueIfNumberOrBooleanMatchesPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "issue.locked",
+			Pattern: "false",
+		}
+
+		// act
+		matches := filter.Matches(payload, nil)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsTrueIfLargeIntegerMatchesPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "pull_request.number",
+			Pattern: "12345678",
+		}
+
+		// act
+		matches := filter.Matches(`{"pull_request":{"number":12345678}}`, nil)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsFalseIfValueAtPathDoesNotMatchPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "action",
+			Pattern: "deleted",
+		}
+
+		// act
+		matches := filter.Matches(payload, nil)
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsFalseIfPathDoesNotExist", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "pull_request.title",
+			Pattern: "!~ WIP.*",
+		}
+
+		// act
+		matches := filter.Matches(payload, nil)
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsFalseIfPayloadIsNotJSON", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Path:    "action",
+			Pattern: ".*",
+		}
+
+		// act
+		matches := filter.Matches("not json", nil)
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsTrueIfAttributeMatchesPattern", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Attribute: "type",
+			Pattern:   "image-pushed",
+		}
+
+		// act
+		matches := filter.Matches("", map[string]string{"type": "image-pushed"})
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsFalseIfAttributeIsMissing", func(t *testing.T) {
+
+		filter := ZiplineeTriggerFilter{
+			Attribute: "type",
+			Pattern:   "image-pushed",
+		}
+
+		// act
+		matches := filter.Matches("", map[string]string{})
+
+		assert.False(t, matches)
+	})
+}
+
+func TestFiltersMatch(t *testing.T) {
+	t.Run("ReturnsTrueIfAllFiltersMatch", func(t *testing.T) {
+
+		filters := []*ZiplineeTriggerFilter{
+			{Path: "pull_request.id", Pattern: "1000000"},
+			{Path: "action", Pattern: "opened"},
+		}
+
+		// act
+		matches := filtersMatch(filters, `{"action":"opened","pull_request":{"id":1000000}}`, nil)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsFalseIfOneFilterDoesNotMatch", func(t *testing.T) {
+
+		filters := []*ZiplineeTriggerFilter{
+			{Path: "pull_request.id", Pattern: "1000000"},
+			{Path: "action", Pattern: "closed"},
+		}
+
+		// act
+		matches := filtersMatch(filters, `{"action":"opened","pull_request":{"id":1000000}}`, nil)
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsTrueIfThereAreNoFilters", func(t *testing.T) {
+
+		// act
+		matches := filtersMatch(nil, "not json", nil)
+
+		assert.True(t, matches)
+	})
+}
@@ -7,6 +7,140 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestZiplineeTriggerFires(t *testing.T) {
+	t.Run("ReturnsTrueIfTriggerTypeMatchesEventTypeAndFires", func(t *testing.T) {
+
+		event := ZiplineeEvent{
+			Git: &ZiplineeGitEvent{
+				Event:      "push",
+				Repository: "github.com/ziplineeci/ziplinee-ci-api",
+				Branch:     "main",
+			},
+		}
+
+		trigger := ZiplineeTrigger{
+			Git: &ZiplineeGitTrigger{
+				Event:      "push",
+				Repository: "github.com/ziplineeci/ziplinee-ci-api",
+				Branch:     "main",
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfTriggerTypeDoesNotMatchEventType", func(t *testing.T) {
+
+		event := ZiplineeEvent{
+			Docker: &ZiplineeDockerEvent{
+				Event: "push",
+				Image: "golang",
+			},
+		}
+
+		trigger := ZiplineeTrigger{
+			Git: &ZiplineeGitTrigger{
+				Event:      "push",
+				Repository: "github.com/ziplineeci/ziplinee-ci-api",
+				Branch:     "main",
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueForManualEventIfManualTriggerIsSet", func(t *testing.T) {
+
+		event := ZiplineeEvent{
+			Manual: &ZiplineeManualEvent{
+				UserID: "user@server.com",
+			},
+		}
+
+		trigger := ZiplineeTrigger{
+			Manual: &ZiplineeManualTrigger{},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsTrueForWebhookEventIfWebhookTriggerMatches", func(t *testing.T) {
+
+		event := ZiplineeEvent{
+			Webhook: &ZiplineeWebhookEvent{
+				Name:    "artifactory",
+				Payload: `{"event_type":"deployed"}`,
+			},
+		}
+
+		trigger := ZiplineeTrigger{
+			Webhook: &ZiplineeWebhookTrigger{
+				Name: "artifactory",
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseForNilEvent", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Manual: &ZiplineeManualTrigger{},
+		}
+
+		// act
+		fires := trigger.Fires(nil)
+
+		assert.False(t, fires)
+	})
+}
+
+func TestZiplineeManualTriggerFires(t *testing.T) {
+	t.Run("ReturnsTrueIfUserMatches", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			UserID: "admin@server.com",
+		}
+
+		trigger := ZiplineeManualTrigger{
+			User: `.+@server\.com`,
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfUserDoesNotMatch", func(t *testing.T) {
+
+		event := ZiplineeManualEvent{
+			UserID: "someone@elsewhere.com",
+		}
+
+		trigger := ZiplineeManualTrigger{
+			User: `.+@server\.com`,
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+}
+
 func TestZiplineePipelineTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventStatusNameAndBranchMatch", func(t *testing.T) {
 
@@ -177,6 +311,114 @@ func TestZiplineePipelineTriggerFires(t *testing.T) {
 	})
 }
 
+func TestZiplineePipelineTriggerFiresWithPaths(t *testing.T) {
+	t.Run("ReturnsTrueIfChangedFileMatchesPaths", func(t *testing.T) {
+
+		event := ZiplineePipelineEvent{
+			Event:        "finished",
+			Status:       "succeeded",
+			RepoSource:   "github.com",
+			RepoOwner:    "ziplineeci",
+			RepoName:     "monorepo",
+			Branch:       "main",
+			ChangedFiles: []string{"services/api/main.go"},
+		}
+
+		trigger := ZiplineePipelineTrigger{
+			Event:  "finished",
+			Status: "succeeded",
+			Name:   "github.com/ziplineeci/monorepo",
+			Branch: "main",
+			Paths:  []string{"services/api/**"},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfAllChangedFilesAreIgnored", func(t *testing.T) {
+
+		event := ZiplineePipelineEvent{
+			Event:        "finished",
+			Status:       "succeeded",
+			RepoSource:   "github.com",
+			RepoOwner:    "ziplineeci",
+			RepoName:     "monorepo",
+			Branch:       "main",
+			ChangedFiles: []string{"docs/index.md"},
+		}
+
+		trigger := ZiplineePipelineTrigger{
+			Event:       "finished",
+			Status:      "succeeded",
+			Name:        "github.com/ziplineeci/monorepo",
+			Branch:      "main",
+			PathsIgnore: []string{"docs/**"},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+}
+
+func TestZiplineePipelineTriggerFiresWithVersion(t *testing.T) {
+	t.Run("ReturnsTrueIfBuildVersionIsInRange", func(t *testing.T) {
+
+		event := ZiplineePipelineEvent{
+			BuildVersion: "2.3.15",
+			RepoSource:   "github.com",
+			RepoOwner:    "ziplineeci",
+			RepoName:     "ziplinee-ci-api",
+			Branch:       "main",
+			Status:       "succeeded",
+			Event:        "finished",
+		}
+
+		trigger := ZiplineePipelineTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-api",
+			Branch:  "main",
+			Version: ">=2.0.0 <3.0.0",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfBuildVersionDoesNotMatchRegex", func(t *testing.T) {
+
+		event := ZiplineePipelineEvent{
+			BuildVersion: "2.3.15-feature-branch",
+			RepoSource:   "github.com",
+			RepoOwner:    "ziplineeci",
+			RepoName:     "ziplinee-ci-api",
+			Branch:       "feature-branch",
+			Status:       "succeeded",
+			Event:        "finished",
+		}
+
+		trigger := ZiplineePipelineTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-api",
+			Branch:  ".+",
+			Version: "=~[0-9.]+",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+}
+
 func TestZiplineeReleaseTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventStatusNameAndBranchMatch", func(t *testing.T) {
 
@@ -299,6 +541,178 @@ func TestZiplineeReleaseTriggerFires(t *testing.T) {
 	})
 }
 
+func TestZiplineeReleaseTriggerFiresWithVersion(t *testing.T) {
+	t.Run("ReturnsTrueIfReleaseVersionIsStable", func(t *testing.T) {
+
+		event := ZiplineeReleaseEvent{
+			ReleaseVersion: "1.0.4",
+			RepoSource:     "github.com",
+			RepoOwner:      "ziplineeci",
+			RepoName:       "ziplinee-ci-api",
+			Target:         "staging",
+			Status:         "succeeded",
+			Event:          "finished",
+		}
+
+		trigger := ZiplineeReleaseTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-api",
+			Target:  "staging",
+			Version: "stable",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfReleaseVersionIsPrerelease", func(t *testing.T) {
+
+		event := ZiplineeReleaseEvent{
+			ReleaseVersion: "1.0.4-feature-branch",
+			RepoSource:     "github.com",
+			RepoOwner:      "ziplineeci",
+			RepoName:       "ziplinee-ci-api",
+			Target:         "staging",
+			Status:         "succeeded",
+			Event:          "finished",
+		}
+
+		trigger := ZiplineeReleaseTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-api",
+			Target:  "staging",
+			Version: "stable",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+}
+
+func TestZiplineeDockerTriggerFires(t *testing.T) {
+	t.Run("ReturnsTrueIfEventImageAndTagMatch", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   "1.0.3",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   `1\.0\..+`,
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsTrueForAnyTagIfTagIsEmpty", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   "latest",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfEventDoesNotMatch", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "delete",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   "1.0.3",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfImageDoesNotMatch", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-web",
+			Tag:   "1.0.3",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfNegativeLookupTagRegexMatches", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   "1.0.3-beta",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+			Tag:   "!~ .+-beta",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueIfPositiveLookupImageRegexMatches", func(t *testing.T) {
+
+		event := ZiplineeDockerEvent{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-web",
+			Tag:   "1.0.3",
+		}
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "=~ ziplinee/ziplinee-ci-(api|web)",
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+}
+
 func TestZiplineeCronTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventTimeMatchesCronSchedule", func(t *testing.T) {
 
@@ -333,6 +747,114 @@ func TestZiplineeCronTriggerFires(t *testing.T) {
 	})
 }
 
+func TestZiplineeCronTriggerFiresWithTimezone(t *testing.T) {
+
+	amsterdam, _ := time.LoadLocation("Europe/Amsterdam")
+
+	testCases := []struct {
+		name      string
+		schedule  string
+		timezone  string
+		eventTime time.Time
+		fires     bool
+	}{
+		{"FiresAtLocalTimeInWinter", "0 9 * * 1-5", "Europe/Amsterdam", time.Date(2019, 1, 7, 8, 0, 0, 0, time.UTC), true},
+		{"FiresAtLocalTimeInSummer", "0 9 * * 1-5", "Europe/Amsterdam", time.Date(2019, 7, 8, 7, 0, 0, 0, time.UTC), true},
+		{"DoesNotFireAtUtcTime", "0 9 * * 1-5", "Europe/Amsterdam", time.Date(2019, 7, 8, 9, 0, 0, 0, time.UTC), false},
+		{"FiresAtUtcTimeWithoutTimezone", "0 9 * * 1-5", "", time.Date(2019, 7, 8, 9, 0, 0, 0, time.UTC), true},
+		{"FiresForEventTimeInOtherLocation", "0 9 * * 1-5", "Europe/Amsterdam", time.Date(2019, 7, 8, 9, 0, 30, 0, amsterdam), true},
+		{"FiresAtShiftedTimeIfSkippedByDaylightSavingTime", "30 2 * * *", "Europe/Amsterdam", time.Date(2019, 3, 31, 3, 30, 0, 0, amsterdam), true},
+		{"FiresAtFirstOccurrenceIfRepeatedByDaylightSavingTime", "30 2 * * *", "Europe/Amsterdam", time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC), true},
+		{"DoesNotFireAtSecondOccurrenceIfRepeatedByDaylightSavingTime", "30 2 * * *", "Europe/Amsterdam", time.Date(2019, 10, 27, 1, 30, 0, 0, time.UTC), false},
+		{"DoesNotFireForInvalidTimezone", "0 9 * * *", "Europe/Atlantis", time.Date(2019, 7, 8, 9, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			trigger := ZiplineeCronTrigger{
+				Schedule: tc.schedule,
+				Timezone: tc.timezone,
+			}
+
+			// act
+			fires := trigger.Fires(&ZiplineeCronEvent{Time: tc.eventTime})
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
+func TestZiplineeCronTriggerNextFireTimes(t *testing.T) {
+	t.Run("ReturnsNextTimesInTimezone", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "0 9 * * 1-5",
+			Timezone: "Europe/Amsterdam",
+		}
+
+		// act
+		times, err := trigger.NextFireTimes(time.Date(2019, 3, 29, 12, 0, 0, 0, time.UTC), 2)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(times)) {
+			// friday before and monday after the switch to daylight saving time
+			assert.Equal(t, time.Date(2019, 4, 1, 7, 0, 0, 0, time.UTC), times[0].UTC())
+			assert.Equal(t, time.Date(2019, 4, 2, 7, 0, 0, 0, time.UTC), times[1].UTC())
+			assert.Equal(t, "Europe/Amsterdam", times[0].Location().String())
+		}
+	})
+
+	t.Run("ReturnsEachWallClockTimeOnceAcrossDaylightSavingTimeTransitions", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "30 * * * *",
+			Timezone: "Europe/Amsterdam",
+		}
+
+		// act
+		times, err := trigger.NextFireTimes(time.Date(2019, 10, 26, 23, 0, 0, 0, time.UTC), 4)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 4, len(times)) {
+			assert.Equal(t, time.Date(2019, 10, 26, 23, 30, 0, 0, time.UTC), times[0].UTC())
+			assert.Equal(t, time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC), times[1].UTC())
+			assert.Equal(t, time.Date(2019, 10, 27, 2, 30, 0, 0, time.UTC), times[2].UTC())
+			assert.Equal(t, time.Date(2019, 10, 27, 3, 30, 0, 0, time.UTC), times[3].UTC())
+		}
+	})
+
+	t.Run("ReturnsNoDuplicateTimesIfSkippedHourIsShifted", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "30 * * * *",
+			Timezone: "Europe/Amsterdam",
+		}
+
+		// act
+		times, err := trigger.NextFireTimes(time.Date(2019, 3, 31, 0, 0, 0, 0, time.UTC), 3)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 3, len(times)) {
+			assert.Equal(t, time.Date(2019, 3, 31, 0, 30, 0, 0, time.UTC), times[0].UTC())
+			assert.Equal(t, time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC), times[1].UTC())
+			assert.Equal(t, time.Date(2019, 3, 31, 2, 30, 0, 0, time.UTC), times[2].UTC())
+		}
+	})
+
+	t.Run("ReturnsErrorIfScheduleIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "0 * * * * *",
+		}
+
+		// act
+		_, err := trigger.NextFireTimes(time.Now(), 1)
+
+		assert.NotNil(t, err)
+	})
+}
+
 func TestZiplineeGitTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventStatusNameAndBranchMatch", func(t *testing.T) {
 
@@ -354,6 +876,83 @@ func TestZiplineeGitTriggerFires(t *testing.T) {
 	})
 }
 
+func TestZiplineeGitTriggerFiresForTagsAndPullRequests(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		trigger ZiplineeGitTrigger
+		event   ZiplineeGitEvent
+		fires   bool
+	}{
+		{"TagMatches", ZiplineeGitTrigger{Event: "tag", Tag: `v.*`}, ZiplineeGitEvent{Event: "tag", Tag: "v1.2.0"}, true},
+		{"TagDoesNotMatch", ZiplineeGitTrigger{Event: "tag", Tag: `v.*`}, ZiplineeGitEvent{Event: "tag", Tag: "release-1.2.0"}, false},
+		{"TagMatchesSemverRegex", ZiplineeGitTrigger{Event: "tag", Tag: `v\d+\.\d+\.\d+`}, ZiplineeGitEvent{Event: "tag", Tag: "v1.2.3"}, true},
+		{"AnyTagMatchesIfTagIsEmpty", ZiplineeGitTrigger{Event: "tag"}, ZiplineeGitEvent{Event: "tag", Tag: "release-1.2.0"}, true},
+		{"TagTriggerDoesNotFireForPush", ZiplineeGitTrigger{Event: "tag"}, ZiplineeGitEvent{Event: "push", Branch: "main"}, false},
+		{"PushTriggerDoesNotFireForTag", ZiplineeGitTrigger{Event: "push", Branch: "main"}, ZiplineeGitEvent{Event: "tag", Tag: "v1.2.0"}, false},
+		{"PullRequestBranchesMatch", ZiplineeGitTrigger{Event: "pull_request", SourceBranch: "feature/.+", TargetBranch: "main"}, ZiplineeGitEvent{Event: "pull_request", SourceBranch: "feature/login", TargetBranch: "main"}, true},
+		{"PullRequestTargetBranchDoesNotMatch", ZiplineeGitTrigger{Event: "pull_request", TargetBranch: "main"}, ZiplineeGitEvent{Event: "pull_request", SourceBranch: "feature/login", TargetBranch: "release"}, false},
+		{"PullRequestSourceBranchDoesNotMatch", ZiplineeGitTrigger{Event: "pull_request", SourceBranch: "feature/.+"}, ZiplineeGitEvent{Event: "pull_request", SourceBranch: "bugfix/login", TargetBranch: "main"}, false},
+		{"AnyPullRequestMatchesIfBranchesAreEmpty", ZiplineeGitTrigger{Event: "pull_request"}, ZiplineeGitEvent{Event: "pull_request", SourceBranch: "bugfix/login", TargetBranch: "main"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.trigger.Repository = "github.com/ziplineeci/ziplinee-ci-manifest"
+			tc.event.Repository = "github.com/ziplineeci/ziplinee-ci-manifest"
+
+			// act
+			fires := tc.trigger.Fires(&tc.event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
+func TestZiplineeGitTriggerFiresWithPaths(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		paths        []string
+		pathsIgnore  []string
+		changedFiles []string
+		fires        bool
+	}{
+		{"NoPathFilters", nil, nil, []string{"services/web/main.go"}, true},
+		{"ChangedFileMatchesPaths", []string{"services/api/**", "go.mod"}, nil, []string{"go.mod"}, true},
+		{"NoChangedFileMatchesPaths", []string{"services/api/**"}, nil, []string{"services/web/main.go"}, false},
+		{"SomeChangedFilesAreNotIgnored", nil, []string{"**/*.md"}, []string{"README.md", "services/api/main.go"}, true},
+		{"AllChangedFilesAreIgnored", nil, []string{"**/*.md"}, []string{"README.md", "docs/index.md"}, false},
+		{"NoChangedFilesInEvent", []string{"services/api/**"}, nil, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			event := ZiplineeGitEvent{
+				Event:        "push",
+				Repository:   "github.com/ziplineeci/monorepo",
+				Branch:       "main",
+				ChangedFiles: tc.changedFiles,
+			}
+
+			trigger := ZiplineeGitTrigger{
+				Event:       "push",
+				Repository:  "github.com/ziplineeci/monorepo",
+				Branch:      "main",
+				Paths:       tc.paths,
+				PathsIgnore: tc.pathsIgnore,
+			}
+
+			// act
+			fires := trigger.Fires(&event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
 func TestZiplineeGithubTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventIsContainedInTriggerEvents", func(t *testing.T) {
 
@@ -380,16 +979,95 @@ func TestZiplineeGithubTriggerFires(t *testing.T) {
 
 		assert.True(t, fires)
 	})
+
+	testCases := []struct {
+		name       string
+		events     []string
+		repository string
+		event      ZiplineeGithubEvent
+		fires      bool
+	}{
+		{"EventNotInTriggerEvents", []string{"push", "create"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "delete", Repository: "github.com/ziplineeci/ziplinee-ci-api"}, false},
+		{"EventMatchingGlob", []string{"pull_request*"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "pull_request_review", Repository: "github.com/ziplineeci/ziplinee-ci-api"}, true},
+		{"EventNotMatchingGlob", []string{"pull_request_*"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "pull_request", Repository: "github.com/ziplineeci/ziplinee-ci-api"}, false},
+		{"EventMatchingRegex", []string{"=~ (issue|pull_request)_comment"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "issue_comment", Repository: "github.com/ziplineeci/ziplinee-ci-api"}, true},
+		{"EventMatchingNegativeRegex", []string{"!~ push"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-api"}, false},
+		{"RepositoryDiffersInCaseOnly", []string{"push"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "push", Repository: "github.com/ZiplineeCI/ziplinee-ci-api"}, true},
+		{"RepositoryDoesNotMatch", []string{"push"}, "github.com/ziplineeci/ziplinee-ci-api", ZiplineeGithubEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-web"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			trigger := ZiplineeGithubTrigger{
+				Events:     tc.events,
+				Repository: tc.repository,
+			}
+
+			// act
+			fires := trigger.Fires(&tc.event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
+func TestZiplineeGithubTriggerFiresWithFilters(t *testing.T) {
+	t.Run("ReturnsTrueIfPullRequestCommentContainsDeployCommand", func(t *testing.T) {
+
+		event := ZiplineeGithubEvent{
+			Event:      "issue_comment",
+			Repository: "github.com/ziplineeci/ziplinee-ci-api",
+			Payload:    `{"action":"created","comment":{"body":"/deploy staging"},"issue":{"pull_request":{"url":"https://api.github.com"}}}`,
+		}
+
+		trigger := ZiplineeGithubTrigger{
+			Events:     []string{"issue_comment"},
+			Repository: "github.com/ziplineeci/ziplinee-ci-api",
+			Filters: []*ZiplineeTriggerFilter{
+				{Path: "comment.body", Pattern: "(?s).*/deploy.*"},
+				{Path: "action", Pattern: "created"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfAnyFilterDoesNotMatch", func(t *testing.T) {
+
+		event := ZiplineeGithubEvent{
+			Event:      "issue_comment",
+			Repository: "github.com/ziplineeci/ziplinee-ci-api",
+			Payload:    `{"action":"edited","comment":{"body":"/deploy staging"}}`,
+		}
+
+		trigger := ZiplineeGithubTrigger{
+			Events:     []string{"issue_comment"},
+			Repository: "github.com/ziplineeci/ziplinee-ci-api",
+			Filters: []*ZiplineeTriggerFilter{
+				{Path: "comment.body", Pattern: "(?s).*/deploy.*"},
+				{Path: "action", Pattern: "created"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
 }
 
 func TestZiplineeBitbucketTriggerFires(t *testing.T) {
 	t.Run("ReturnsTrueIfEventIsContainedInTriggerEvents", func(t *testing.T) {
 
-		event := ZiplineeGithubEvent{
+		event := ZiplineeBitbucketEvent{
 			Event: "pullrequest:comment_created",
 		}
 
-		trigger := ZiplineeGithubTrigger{
+		trigger := ZiplineeBitbucketTrigger{
 			Events: []string{
 				"pullrequest:fulfilled",
 				"pullrequest:rejected",
@@ -404,6 +1082,123 @@ func TestZiplineeBitbucketTriggerFires(t *testing.T) {
 
 		assert.True(t, fires)
 	})
+
+	testCases := []struct {
+		name       string
+		events     []string
+		repository string
+		event      ZiplineeBitbucketEvent
+		fires      bool
+	}{
+		{"EventNotInTriggerEvents", []string{"repo:push"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "repo:fork", Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api"}, false},
+		{"EventMatchingGlob", []string{"pullrequest:*"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "pullrequest:created", Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api"}, true},
+		{"EventNotMatchingGlob", []string{"pullrequest:*"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "repo:push", Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api"}, false},
+		{"EventMatchingRegex", []string{"=~ pullrequest:comment_(created|updated)"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "pullrequest:comment_updated", Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api"}, true},
+		{"RepositoryDiffersInCaseOnly", []string{"repo:push"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "repo:push", Repository: "bitbucket.org/ZiplineeCI/Ziplinee-CI-API"}, true},
+		{"RepositoryDoesNotMatch", []string{"repo:push"}, "bitbucket.org/ziplineeci/ziplinee-ci-api", ZiplineeBitbucketEvent{Event: "repo:push", Repository: "bitbucket.org/ziplineeci/ziplinee-ci-web"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			trigger := ZiplineeBitbucketTrigger{
+				Events:     tc.events,
+				Repository: tc.repository,
+			}
+
+			// act
+			fires := trigger.Fires(&tc.event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
+func TestZiplineeBitbucketTriggerFiresWithFilters(t *testing.T) {
+	t.Run("ReturnsTrueIfPayloadMatchesFilter", func(t *testing.T) {
+
+		event := ZiplineeBitbucketEvent{
+			Event:      "pullrequest:comment_created",
+			Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api",
+			Payload:    `{"comment":{"content":{"raw":"please /deploy"}}}`,
+		}
+
+		trigger := ZiplineeBitbucketTrigger{
+			Events:     []string{"pullrequest:comment_*"},
+			Repository: "bitbucket.org/ziplineeci/ziplinee-ci-api",
+			Filters: []*ZiplineeTriggerFilter{
+				{Path: "comment.content.raw", Pattern: ".*/deploy.*"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+}
+
+func TestZiplineeGitlabTriggerFires(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		events     []string
+		repository string
+		event      ZiplineeGitlabEvent
+		fires      bool
+	}{
+		{"EventInTriggerEvents", []string{"Push Hook", "Tag Push Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "Push Hook", Repository: "gitlab.com/ziplineeci/ziplinee-ci-api"}, true},
+		{"EventNotInTriggerEvents", []string{"Push Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "Merge Request Hook", Repository: "gitlab.com/ziplineeci/ziplinee-ci-api"}, false},
+		{"EventMatchingGlob", []string{"*Push Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "Tag Push Hook", Repository: "gitlab.com/ziplineeci/ziplinee-ci-api"}, true},
+		{"EventWithoutRepository", []string{"System Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "System Hook"}, true},
+		{"RepositoryDiffersInCaseOnly", []string{"Push Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "Push Hook", Repository: "gitlab.com/ZiplineeCI/ziplinee-ci-api"}, true},
+		{"RepositoryDoesNotMatch", []string{"Push Hook"}, "gitlab.com/ziplineeci/ziplinee-ci-api", ZiplineeGitlabEvent{Event: "Push Hook", Repository: "gitlab.com/ziplineeci/ziplinee-ci-web"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			trigger := ZiplineeGitlabTrigger{
+				Events:     tc.events,
+				Repository: tc.repository,
+			}
+
+			// act
+			fires := trigger.Fires(&tc.event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
+}
+
+func TestZiplineeWebhookTriggerFires(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		trigger ZiplineeWebhookTrigger
+		event   ZiplineeWebhookEvent
+		fires   bool
+	}{
+		{"NameMatches", ZiplineeWebhookTrigger{Name: "artifactory"}, ZiplineeWebhookEvent{Name: "artifactory"}, true},
+		{"NameDiffersInCaseOnly", ZiplineeWebhookTrigger{Name: "artifactory"}, ZiplineeWebhookEvent{Name: "Artifactory"}, true},
+		{"NameDoesNotMatch", ZiplineeWebhookTrigger{Name: "artifactory"}, ZiplineeWebhookEvent{Name: "jira"}, false},
+		{"HeaderMatches", ZiplineeWebhookTrigger{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_(created|updated)"}}, ZiplineeWebhookEvent{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_created"}}, true},
+		{"HeaderNameDiffersInCaseOnly", ZiplineeWebhookTrigger{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_created"}}, ZiplineeWebhookEvent{Name: "jira", Headers: map[string]string{"x-event-type": "issue_created"}}, true},
+		{"HeaderDoesNotMatch", ZiplineeWebhookTrigger{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_created"}}, ZiplineeWebhookEvent{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_deleted"}}, false},
+		{"HeaderIsMissing", ZiplineeWebhookTrigger{Name: "jira", Headers: map[string]string{"X-Event-Type": "issue_created"}}, ZiplineeWebhookEvent{Name: "jira"}, false},
+		{"PayloadFilterMatches", ZiplineeWebhookTrigger{Name: "artifactory", Filters: []*ZiplineeTriggerFilter{{Path: "data.repo_key", Pattern: "docker-local"}}}, ZiplineeWebhookEvent{Name: "artifactory", Payload: `{"data":{"repo_key":"docker-local"}}`}, true},
+		{"PayloadFilterDoesNotMatch", ZiplineeWebhookTrigger{Name: "artifactory", Filters: []*ZiplineeTriggerFilter{{Path: "data.repo_key", Pattern: "docker-local"}}}, ZiplineeWebhookEvent{Name: "artifactory", Payload: `{"data":{"repo_key":"npm-local"}}`}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// act
+			fires := tc.trigger.Fires(&tc.event)
+
+			assert.Equal(t, tc.fires, fires)
+		})
+	}
 }
 
 func TestZiplineePubsubTriggerFires(t *testing.T) {
@@ -514,4 +1309,77 @@ func TestZiplineePubsubTriggerFires(t *testing.T) {
 
 		assert.False(t, fires)
 	})
+
+	t.Run("ReturnsTrueIfMessageAttributeMatchesFilter", func(t *testing.T) {
+
+		event := ZiplineePubSubEvent{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Message: PubsubMessage{
+				Attributes: map[string]string{"type": "image-pushed"},
+			},
+		}
+
+		trigger := ZiplineePubSubTrigger{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type", Pattern: "image-pushed"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
+
+	t.Run("ReturnsFalseIfMessageAttributeDoesNotMatchFilter", func(t *testing.T) {
+
+		event := ZiplineePubSubEvent{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Message: PubsubMessage{
+				Attributes: map[string]string{"type": "image-deleted"},
+			},
+		}
+
+		trigger := ZiplineePubSubTrigger{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type", Pattern: "image-pushed"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.False(t, fires)
+	})
+
+	t.Run("ReturnsTrueIfBase64EncodedMessageDataMatchesFilter", func(t *testing.T) {
+
+		event := ZiplineePubSubEvent{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Message: PubsubMessage{
+				// {"image":"ziplinee/ziplinee-ci-api"}
+				Data: "eyJpbWFnZSI6InppcGxpbmVlL3ppcGxpbmVlLWNpLWFwaSJ9",
+			},
+		}
+
+		trigger := ZiplineePubSubTrigger{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Filters: []*ZiplineeTriggerFilter{
+				{Path: "image", Pattern: "ziplinee/.+"},
+			},
+		}
+
+		// act
+		fires := trigger.Fires(&event)
+
+		assert.True(t, fires)
+	})
 }
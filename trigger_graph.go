@@ -0,0 +1,366 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TriggerGraphNode is a build, release or bot of a pipeline that can be triggered by, or trigger, other nodes
+type TriggerGraphNode struct {
+	PipelineName string
+	Type         TriggerType
+	// Name is the release target or bot name, or the release target pattern for pipelines outside the graph; empty for builds
+	Name string
+	// External indicates the pipeline is referenced by a trigger but has no manifest in the graph
+	External bool
+}
+
+// String returns a readable name for the node, like github.com/ziplineeci/ziplinee-ci-api release production
+func (n TriggerGraphNode) String() string {
+	if n.Type == TriggerTypeBuild {
+		return n.PipelineName
+	}
+
+	return fmt.Sprintf("%v %v %v", n.PipelineName, n.Type, n.Name)
+}
+
+// TriggerGraphEdge indicates the From node finishing or starting fires a trigger that starts the To node
+type TriggerGraphEdge struct {
+	From    TriggerGraphNode
+	To      TriggerGraphNode
+	Trigger *ZiplineeTrigger
+}
+
+// Label describes the event on the From node the trigger fires for, like finished succeeded
+func (e TriggerGraphEdge) Label() string {
+	switch {
+	case e.Trigger.Pipeline != nil:
+		if e.Trigger.Pipeline.Event == "finished" {
+			return fmt.Sprintf("%v %v", e.Trigger.Pipeline.Event, e.Trigger.Pipeline.Status)
+		}
+		return e.Trigger.Pipeline.Event
+	case e.Trigger.Release != nil:
+		if e.Trigger.Release.Event == "finished" {
+			return fmt.Sprintf("%v %v", e.Trigger.Release.Event, e.Trigger.Release.Status)
+		}
+		return e.Trigger.Release.Event
+	}
+
+	return ""
+}
+
+// TriggerGraph is the directed graph of builds, releases and bots of many pipelines connected by their pipeline and release triggers
+type TriggerGraph struct {
+	Nodes []TriggerGraphNode
+	Edges []TriggerGraphEdge
+}
+
+// NewTriggerGraph builds the graph for a set of manifests keyed by full pipeline name like github.com/ziplineeci/ziplinee-ci-manifest;
+// an edge is added whenever a trigger can fire for a node, without taking branch filters into account
+func NewTriggerGraph(manifests map[string]*ZiplineeManifest) *TriggerGraph {
+
+	graph := &TriggerGraph{
+		Nodes: []TriggerGraphNode{},
+		Edges: []TriggerGraphEdge{},
+	}
+	nodes := map[TriggerGraphNode]bool{}
+	addNode := func(n TriggerGraphNode) {
+		if !nodes[n] {
+			nodes[n] = true
+			graph.Nodes = append(graph.Nodes, n)
+		}
+	}
+
+	names := make([]string, 0, len(manifests))
+	for name, m := range manifests {
+		if m != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// trigger names are compared case insensitive, so look up pipelines by lowercase name
+	pipelines := map[string]string{}
+	for _, name := range names {
+		pipelines[strings.ToLower(name)] = name
+	}
+
+	// add the nodes for all pipelines first, so they're ordered by pipeline
+	for _, name := range names {
+		m := manifests[name]
+		addNode(TriggerGraphNode{PipelineName: name, Type: TriggerTypeBuild})
+		for _, r := range m.Releases {
+			addNode(TriggerGraphNode{PipelineName: name, Type: TriggerTypeRelease, Name: r.Name})
+		}
+		for _, b := range m.Bots {
+			addNode(TriggerGraphNode{PipelineName: name, Type: TriggerTypeBot, Name: b.Name})
+		}
+	}
+
+	addEdges := func(to TriggerGraphNode, triggers []*ZiplineeTrigger) {
+		for _, t := range triggers {
+			if t == nil {
+				continue
+			}
+
+			// the sub-triggers of 'all' and 'any' triggers each add an edge
+			for _, leaf := range t.leafTriggers() {
+				// only pipeline and release triggers fire for events of other nodes
+				if leaf.Pipeline == nil && leaf.Release == nil {
+					continue
+				}
+
+				// copy so replacing self doesn't change the manifest
+				trigger := ZiplineeTrigger{
+					Name:          t.Name,
+					BuildAction:   t.BuildAction,
+					ReleaseAction: t.ReleaseAction,
+					BotAction:     t.BotAction,
+				}
+				if leaf.Pipeline != nil {
+					pipelineTrigger := *leaf.Pipeline
+					trigger.Pipeline = &pipelineTrigger
+				}
+				if leaf.Release != nil {
+					releaseTrigger := *leaf.Release
+					trigger.Release = &releaseTrigger
+				}
+				trigger.ReplaceSelf(to.PipelineName)
+
+				for _, from := range triggerGraphSources(&trigger, manifests, pipelines) {
+					addNode(from)
+					graph.Edges = append(graph.Edges, TriggerGraphEdge{From: from, To: to, Trigger: &trigger})
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		m := manifests[name]
+		addEdges(TriggerGraphNode{PipelineName: name, Type: TriggerTypeBuild}, m.Triggers)
+		for _, r := range m.Releases {
+			addEdges(TriggerGraphNode{PipelineName: name, Type: TriggerTypeRelease, Name: r.Name}, r.Triggers)
+		}
+		for _, b := range m.Bots {
+			addEdges(TriggerGraphNode{PipelineName: name, Type: TriggerTypeBot, Name: b.Name}, b.Triggers)
+		}
+	}
+
+	return graph
+}
+
+// triggerGraphSources returns the nodes whose events can fire the trigger
+func triggerGraphSources(t *ZiplineeTrigger, manifests map[string]*ZiplineeManifest, pipelines map[string]string) (sources []TriggerGraphNode) {
+	switch {
+	case t.Pipeline != nil:
+		name, found := pipelines[strings.ToLower(t.Pipeline.Name)]
+		if !found {
+			return []TriggerGraphNode{{PipelineName: t.Pipeline.Name, Type: TriggerTypeBuild, External: true}}
+		}
+		return []TriggerGraphNode{{PipelineName: name, Type: TriggerTypeBuild}}
+
+	case t.Release != nil:
+		name, found := pipelines[strings.ToLower(t.Release.Name)]
+		if !found {
+			return []TriggerGraphNode{{PipelineName: t.Release.Name, Type: TriggerTypeRelease, Name: t.Release.Target, External: true}}
+		}
+		// the target is a regex, so it can match multiple releases
+		for _, r := range manifests[name].Releases {
+			if match, err := regexMatch(t.Release.Target, r.Name); err == nil && match {
+				sources = append(sources, TriggerGraphNode{PipelineName: name, Type: TriggerTypeRelease, Name: r.Name})
+			}
+		}
+	}
+
+	return
+}
+
+// Cycles returns a cycle for every group of nodes that can keep triggering each other, as the path of nodes ending with the node it starts with
+func (g *TriggerGraph) Cycles() (cycles [][]TriggerGraphNode) {
+
+	successors := map[TriggerGraphNode][]TriggerGraphNode{}
+	for _, e := range g.Edges {
+		successors[e.From] = append(successors[e.From], e.To)
+	}
+
+	for _, component := range g.stronglyConnectedComponents(successors) {
+		inComponent := map[TriggerGraphNode]bool{}
+		for _, n := range component {
+			inComponent[n] = true
+		}
+
+		start := component[0]
+		if len(component) == 1 && !triggerGraphNodesContain(successors[start], start) {
+			continue
+		}
+
+		// breadth first search for the shortest path within the component back to the start
+		previous := map[TriggerGraphNode]TriggerGraphNode{}
+		visited := map[TriggerGraphNode]bool{}
+		queue := []TriggerGraphNode{start}
+		var last TriggerGraphNode
+	search:
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			for _, s := range successors[n] {
+				if s == start {
+					last = n
+					break search
+				}
+				if inComponent[s] && !visited[s] {
+					visited[s] = true
+					previous[s] = n
+					queue = append(queue, s)
+				}
+			}
+		}
+
+		cycle := []TriggerGraphNode{start}
+		for n := last; n != start; n = previous[n] {
+			cycle = append(cycle, n)
+		}
+		// the path was built backwards, so reverse everything after the start and close the loop
+		for i, j := 1, len(cycle)-1; i < j; i, j = i+1, j-1 {
+			cycle[i], cycle[j] = cycle[j], cycle[i]
+		}
+		cycle = append(cycle, start)
+
+		cycles = append(cycles, cycle)
+	}
+
+	return
+}
+
+// stronglyConnectedComponents uses tarjan's algorithm to group nodes that can reach each other, with nodes in graph order
+func (g *TriggerGraph) stronglyConnectedComponents(successors map[TriggerGraphNode][]TriggerGraphNode) (components [][]TriggerGraphNode) {
+
+	order := map[TriggerGraphNode]int{}
+	for i, n := range g.Nodes {
+		order[n] = i
+	}
+
+	index := 0
+	indices := map[TriggerGraphNode]int{}
+	lowlinks := map[TriggerGraphNode]int{}
+	onStack := map[TriggerGraphNode]bool{}
+	stack := []TriggerGraphNode{}
+
+	var connect func(n TriggerGraphNode)
+	connect = func(n TriggerGraphNode) {
+		indices[n] = index
+		lowlinks[n] = index
+		index++
+		stack = append(stack, n)
+		onStack[n] = true
+
+		for _, s := range successors[n] {
+			if _, visited := indices[s]; !visited {
+				connect(s)
+				if lowlinks[s] < lowlinks[n] {
+					lowlinks[n] = lowlinks[s]
+				}
+			} else if onStack[s] && indices[s] < lowlinks[n] {
+				lowlinks[n] = indices[s]
+			}
+		}
+
+		if lowlinks[n] == indices[n] {
+			component := []TriggerGraphNode{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == n {
+					break
+				}
+			}
+			sort.Slice(component, func(i, j int) bool {
+				return order[component[i]] < order[component[j]]
+			})
+			components = append(components, component)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, visited := indices[n]; !visited {
+			connect(n)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return order[components[i][0]] < order[components[j][0]]
+	})
+
+	return
+}
+
+// DOT returns the graph in graphviz dot format
+func (g *TriggerGraph) DOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph triggers {\n")
+	for _, n := range g.Nodes {
+		attributes := []string{fmt.Sprintf("label=%q", n.String())}
+		switch n.Type {
+		case TriggerTypeRelease:
+			attributes = append(attributes, "shape=ellipse")
+		case TriggerTypeBot:
+			attributes = append(attributes, "shape=hexagon")
+		default:
+			attributes = append(attributes, "shape=box")
+		}
+		if n.External {
+			attributes = append(attributes, "style=dashed")
+		}
+		sb.WriteString(fmt.Sprintf("  %q [%v];\n", n.String(), strings.Join(attributes, ", ")))
+	}
+	for _, e := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", e.From.String(), e.To.String(), e.Label()))
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// Mermaid returns the graph as mermaid flowchart
+func (g *TriggerGraph) Mermaid() string {
+	var sb strings.Builder
+
+	ids := map[TriggerGraphNode]string{}
+	sb.WriteString("graph LR\n")
+	for i, n := range g.Nodes {
+		ids[n] = fmt.Sprintf("n%v", i)
+		label := strings.ReplaceAll(n.String(), `"`, "#quot;")
+		switch n.Type {
+		case TriggerTypeRelease:
+			sb.WriteString(fmt.Sprintf("  %v([\"%v\"])\n", ids[n], label))
+		case TriggerTypeBot:
+			sb.WriteString(fmt.Sprintf("  %v{{\"%v\"}}\n", ids[n], label))
+		default:
+			sb.WriteString(fmt.Sprintf("  %v[\"%v\"]\n", ids[n], label))
+		}
+	}
+	for _, e := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %v -->|%v| %v\n", ids[e.From], e.Label(), ids[e.To]))
+	}
+	for _, n := range g.Nodes {
+		if n.External {
+			sb.WriteString(fmt.Sprintf("  style %v stroke-dasharray: 5 5\n", ids[n]))
+		}
+	}
+
+	return sb.String()
+}
+
+func triggerGraphNodesContain(nodes []TriggerGraphNode, node TriggerGraphNode) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerGraph(t *testing.T) {
+
+	// api builds when contracts is built, and releases to production after its own build; contracts releases after api released
+	// to production, and builds again after its own release, which closes the loop
+	api := readTriggerIndexTestManifest(t, `
+triggers:
+- pipeline:
+    name: github.com/ziplineeci/ziplinee-ci-contracts
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - pipeline:
+        name: self
+    stages:
+      deploy:
+        image: alpine`)
+
+	contracts := readTriggerIndexTestManifest(t, `
+triggers:
+- release:
+    name: github.com/ziplineeci/ziplinee-ci-contracts
+    target: stable
+stages:
+  build:
+    image: golang
+releases:
+  stable:
+    triggers:
+    - release:
+        name: github.com/ziplineeci/ziplinee-ci-api
+        target: prod.*
+    stages:
+      tag:
+        image: alpine`)
+
+	web := readTriggerIndexTestManifest(t, `
+triggers:
+- pipeline:
+    name: github.com/ziplineeci/ziplinee-ci-api
+    event: started
+- pipeline:
+    name: github.com/ziplineeci/ziplinee-ci-unknown
+stages:
+  build:
+    image: golang
+bots:
+  notify:
+    triggers:
+    - release:
+        name: github.com/ziplineeci/ziplinee-ci-api
+        target: production
+    stages:
+      notify:
+        image: alpine`)
+
+	manifests := map[string]*ZiplineeManifest{
+		"github.com/ziplineeci/ziplinee-ci-api":       api,
+		"github.com/ziplineeci/ziplinee-ci-contracts": contracts,
+		"github.com/ziplineeci/ziplinee-ci-web":       web,
+	}
+
+	apiBuild := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-api", Type: TriggerTypeBuild}
+	apiProduction := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-api", Type: TriggerTypeRelease, Name: "production"}
+	contractsBuild := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-contracts", Type: TriggerTypeBuild}
+	contractsStable := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-contracts", Type: TriggerTypeRelease, Name: "stable"}
+	webBuild := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-web", Type: TriggerTypeBuild}
+	webNotify := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-web", Type: TriggerTypeBot, Name: "notify"}
+	unknownBuild := TriggerGraphNode{PipelineName: "github.com/ziplineeci/ziplinee-ci-unknown", Type: TriggerTypeBuild, External: true}
+
+	t.Run("ReturnsNodesForBuildsReleasesBotsAndExternalPipelines", func(t *testing.T) {
+
+		// act
+		graph := NewTriggerGraph(manifests)
+
+		assert.Equal(t, []TriggerGraphNode{apiBuild, apiProduction, contractsBuild, contractsStable, webBuild, webNotify, unknownBuild}, graph.Nodes)
+	})
+
+	t.Run("ReturnsEdgesFromTriggeringToTriggeredNodes", func(t *testing.T) {
+
+		// act
+		graph := NewTriggerGraph(manifests)
+
+		edges := [][2]TriggerGraphNode{}
+		for _, e := range graph.Edges {
+			edges = append(edges, [2]TriggerGraphNode{e.From, e.To})
+		}
+		assert.Equal(t, [][2]TriggerGraphNode{
+			{contractsBuild, apiBuild},
+			{apiBuild, apiProduction},
+			{contractsStable, contractsBuild},
+			{apiProduction, contractsStable},
+			{apiBuild, webBuild},
+			{unknownBuild, webBuild},
+			{apiProduction, webNotify},
+		}, edges)
+		assert.Equal(t, "finished succeeded", graph.Edges[0].Label())
+		assert.Equal(t, "started", graph.Edges[4].Label())
+	})
+
+	t.Run("DoesNotChangeManifests", func(t *testing.T) {
+
+		// act
+		NewTriggerGraph(manifests)
+
+		assert.Equal(t, "self", api.Releases[0].Triggers[0].Pipeline.Name)
+	})
+
+	t.Run("ReturnsCycleWithPathInvolved", func(t *testing.T) {
+
+		graph := NewTriggerGraph(manifests)
+
+		// act
+		cycles := graph.Cycles()
+
+		if assert.Equal(t, 1, len(cycles)) {
+			assert.Equal(t, []TriggerGraphNode{apiBuild, apiProduction, contractsStable, contractsBuild, apiBuild}, cycles[0])
+		}
+	})
+
+	t.Run("ReturnsCycleForReleaseTriggeringItself", func(t *testing.T) {
+
+		graph := NewTriggerGraph(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, `
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - release:
+        name: self
+        target: production
+    stages:
+      deploy:
+        image: alpine`),
+		})
+
+		// act
+		cycles := graph.Cycles()
+
+		if assert.Equal(t, 1, len(cycles)) {
+			assert.Equal(t, []TriggerGraphNode{apiProduction, apiProduction}, cycles[0])
+		}
+	})
+
+	t.Run("ReturnsNoCyclesForAcyclicGraph", func(t *testing.T) {
+
+		graph := NewTriggerGraph(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": api,
+			"github.com/ziplineeci/ziplinee-ci-web": web,
+		})
+
+		// act
+		cycles := graph.Cycles()
+
+		assert.Equal(t, 0, len(cycles))
+	})
+
+	t.Run("ReturnsDOT", func(t *testing.T) {
+
+		graph := NewTriggerGraph(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": api,
+		})
+
+		// act
+		dot := graph.DOT()
+
+		assert.Equal(t, `digraph triggers {
+  "github.com/ziplineeci/ziplinee-ci-api" [label="github.com/ziplineeci/ziplinee-ci-api", shape=box];
+  "github.com/ziplineeci/ziplinee-ci-api release production" [label="github.com/ziplineeci/ziplinee-ci-api release production", shape=ellipse];
+  "github.com/ziplineeci/ziplinee-ci-contracts" [label="github.com/ziplineeci/ziplinee-ci-contracts", shape=box, style=dashed];
+  "github.com/ziplineeci/ziplinee-ci-contracts" -> "github.com/ziplineeci/ziplinee-ci-api" [label="finished succeeded"];
+  "github.com/ziplineeci/ziplinee-ci-api" -> "github.com/ziplineeci/ziplinee-ci-api release production" [label="finished succeeded"];
+}
+`, dot)
+	})
+
+	t.Run("ReturnsMermaid", func(t *testing.T) {
+
+		graph := NewTriggerGraph(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-web": web,
+		})
+
+		// act
+		mermaid := graph.Mermaid()
+
+		assert.Equal(t, `graph LR
+  n0["github.com/ziplineeci/ziplinee-ci-web"]
+  n1{{"github.com/ziplineeci/ziplinee-ci-web bot notify"}}
+  n2["github.com/ziplineeci/ziplinee-ci-api"]
+  n3["github.com/ziplineeci/ziplinee-ci-unknown"]
+  n4(["github.com/ziplineeci/ziplinee-ci-api release production"])
+  n2 -->|started| n0
+  n3 -->|finished succeeded| n0
+  n4 -->|finished succeeded| n1
+  style n2 stroke-dasharray: 5 5
+  style n3 stroke-dasharray: 5 5
+  style n4 stroke-dasharray: 5 5
+`, mermaid)
+	})
+}
@@ -0,0 +1,372 @@
+package manifest
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// triggerIndexAnyKey is the bucket key for triggers that can't be bucketed by a literal value, like regex patterns
+const triggerIndexAnyKey = "*"
+
+// TriggerIndex holds the triggers of many pipelines bucketed by trigger type and literal values like pipeline name, repository,
+// pubsub topic and cron minute, so the triggers firing for an event are found without evaluating all of them; it's safe for
+// concurrent use and pipelines can be added and removed when their manifest changes
+type TriggerIndex struct {
+	mutex     sync.RWMutex
+	sequence  uint64
+	pipelines map[string][]*triggerIndexEntry
+	buckets   map[string]map[*triggerIndexEntry]struct{}
+	timezones map[string]*triggerIndexTimezone
+}
+
+// TriggerIndexMatch is a trigger that fired for an event, together with the pipeline it belongs to and the action to take
+type TriggerIndexMatch struct {
+	PipelineName string
+	ZiplineeTriggerMatch
+}
+
+type triggerIndexEntry struct {
+	sequence     uint64
+	pipelineName string
+	match        ZiplineeTriggerMatch
+	keys         []string
+}
+
+type triggerIndexTimezone struct {
+	location *time.Location
+	count    int
+}
+
+// NewTriggerIndex returns an index with the triggers of all manifests, keyed by full pipeline name like github.com/ziplineeci/ziplinee-ci-manifest
+func NewTriggerIndex(manifests map[string]*ZiplineeManifest) *TriggerIndex {
+	index := &TriggerIndex{
+		pipelines: map[string][]*triggerIndexEntry{},
+		buckets:   map[string]map[*triggerIndexEntry]struct{}{},
+		timezones: map[string]*triggerIndexTimezone{},
+	}
+
+	// add in a fixed order so matches for the same event are always returned in the same order
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		index.Add(name, manifests[name])
+	}
+
+	return index
+}
+
+// Add indexes the build, release and bot triggers of a pipeline's manifest, replacing the triggers indexed for it before
+func (i *TriggerIndex) Add(pipelineName string, manifest *ZiplineeManifest) {
+
+	// copy the manifest so resolving self and hashed cron schedules doesn't change the manifest passed in
+	var entries []*triggerIndexEntry
+	if manifest != nil {
+		manifestCopy := manifest.DeepCopy()
+
+		for _, t := range manifestCopy.Triggers {
+			entries = appendTriggerIndexEntry(entries, pipelineName, TriggerTypeBuild, "", nil, t)
+		}
+		for _, r := range manifestCopy.Releases {
+			for _, t := range r.Triggers {
+				entries = appendTriggerIndexEntry(entries, pipelineName, TriggerTypeRelease, r.Name, r.Concurrency, t)
+			}
+		}
+		for _, b := range manifestCopy.Bots {
+			for _, t := range b.Triggers {
+				entries = appendTriggerIndexEntry(entries, pipelineName, TriggerTypeBot, b.Name, b.Concurrency, t)
+			}
+		}
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.remove(pipelineName)
+
+	for _, e := range entries {
+		i.sequence++
+		e.sequence = i.sequence
+		e.keys = triggerIndexKeys(e.match.Trigger)
+		for _, key := range e.keys {
+			if _, ok := i.buckets[key]; !ok {
+				i.buckets[key] = map[*triggerIndexEntry]struct{}{}
+			}
+			i.buckets[key][e] = struct{}{}
+		}
+		for _, leaf := range e.match.Trigger.leafTriggers() {
+			if leaf.Cron != nil {
+				i.addTimezone(leaf.Cron.Timezone)
+			}
+		}
+	}
+	if len(entries) > 0 {
+		i.pipelines[pipelineName] = entries
+	}
+}
+
+// Remove removes all triggers of a pipeline from the index
+func (i *TriggerIndex) Remove(pipelineName string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.remove(pipelineName)
+}
+
+// Len returns the number of indexed triggers
+func (i *TriggerIndex) Len() (count int) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	for _, entries := range i.pipelines {
+		count += len(entries)
+	}
+
+	return
+}
+
+// Match returns the triggers of all pipelines that fire for the event, in the order they were added; 'all' triggers get no events
+// before it, use MatchEvents to pass them
+func (i *TriggerIndex) Match(event *ZiplineeEvent) []TriggerIndexMatch {
+	return i.MatchEvents([]*ZiplineeEvent{event})
+}
+
+// MatchEvents returns the triggers of all pipelines that fire for the last event in the stream, taking the events before it into
+// account for 'all' triggers, in the order they were added; the events are ordered by the time they happened
+func (i *TriggerIndex) MatchEvents(events []*ZiplineeEvent) []TriggerIndexMatch {
+	matches := make([]TriggerIndexMatch, 0)
+	if len(events) == 0 || events[len(events)-1] == nil {
+		return matches
+	}
+	event := events[len(events)-1]
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	// a trigger can be in multiple buckets for the event, so collect them in a set before evaluating
+	candidates := map[*triggerIndexEntry]struct{}{}
+	for _, key := range i.eventKeys(event) {
+		for e := range i.buckets[key] {
+			candidates[e] = struct{}{}
+		}
+	}
+
+	fired := make([]*triggerIndexEntry, 0)
+	for e := range candidates {
+		if e.match.Trigger.FiresForEvents(events) {
+			fired = append(fired, e)
+		}
+	}
+	sort.Slice(fired, func(a, b int) bool {
+		return fired[a].sequence < fired[b].sequence
+	})
+
+	for _, e := range fired {
+		matches = append(matches, TriggerIndexMatch{
+			PipelineName:         e.pipelineName,
+			ZiplineeTriggerMatch: e.match,
+		})
+	}
+
+	return matches
+}
+
+func (i *TriggerIndex) remove(pipelineName string) {
+	for _, e := range i.pipelines[pipelineName] {
+		for _, key := range e.keys {
+			delete(i.buckets[key], e)
+			if len(i.buckets[key]) == 0 {
+				delete(i.buckets, key)
+			}
+		}
+		for _, leaf := range e.match.Trigger.leafTriggers() {
+			if leaf.Cron != nil {
+				i.removeTimezone(leaf.Cron.Timezone)
+			}
+		}
+	}
+	delete(i.pipelines, pipelineName)
+}
+
+func (i *TriggerIndex) addTimezone(timezone string) {
+	if tz, ok := i.timezones[timezone]; ok {
+		tz.count++
+		return
+	}
+
+	location, err := loadCronLocation(timezone)
+	if err != nil {
+		// invalid timezones never fire, so there's no need to look them up
+		location = nil
+	}
+	i.timezones[timezone] = &triggerIndexTimezone{location: location, count: 1}
+}
+
+func (i *TriggerIndex) removeTimezone(timezone string) {
+	if tz, ok := i.timezones[timezone]; ok {
+		tz.count--
+		if tz.count <= 0 {
+			delete(i.timezones, timezone)
+		}
+	}
+}
+
+// eventKeys returns the keys of all buckets that can contain triggers firing for the event
+func (i *TriggerIndex) eventKeys(e *ZiplineeEvent) (keys []string) {
+	switch {
+	case e.Pipeline != nil:
+		return eventIndexKeys("pipeline", strings.ToLower(e.Pipeline.RepoSource+"/"+e.Pipeline.RepoOwner+"/"+e.Pipeline.RepoName))
+	case e.Release != nil:
+		return eventIndexKeys("release", strings.ToLower(e.Release.RepoSource+"/"+e.Release.RepoOwner+"/"+e.Release.RepoName))
+	case e.Git != nil:
+		return eventIndexKeys("git", strings.ToLower(e.Git.Repository))
+	case e.Docker != nil:
+		return eventIndexKeys("docker", e.Docker.Image)
+	case e.Cron != nil:
+		keys = []string{triggerIndexKey("cron", triggerIndexAnyKey)}
+		for timezone, tz := range i.timezones {
+			if tz.location != nil {
+				keys = append(keys, triggerIndexKey("cron", timezone, strconv.Itoa(e.Cron.Time.In(tz.location).Minute())))
+			}
+		}
+		return
+	case e.PubSub != nil:
+		return eventIndexKeys("pubsub", e.PubSub.Topic)
+	case e.Github != nil:
+		return repositoryEventIndexKeys("github", e.Github.Repository)
+	case e.Bitbucket != nil:
+		return repositoryEventIndexKeys("bitbucket", e.Bitbucket.Repository)
+	case e.Gitlab != nil:
+		return repositoryEventIndexKeys("gitlab", e.Gitlab.Repository)
+	case e.Webhook != nil:
+		return eventIndexKeys("webhook", strings.ToLower(e.Webhook.Name))
+	case e.Manual != nil:
+		return []string{triggerIndexKey("manual")}
+	}
+
+	return
+}
+
+func eventIndexKeys(triggerType, value string) []string {
+	return []string{triggerIndexKey(triggerType, value), triggerIndexKey(triggerType, triggerIndexAnyKey)}
+}
+
+// repositoryEventIndexKeys returns the keys for integration events, which fire all triggers of that type if they have no repository
+func repositoryEventIndexKeys(triggerType, repository string) []string {
+	if repository == "" {
+		return []string{triggerIndexKey(triggerType)}
+	}
+
+	return eventIndexKeys(triggerType, strings.ToLower(repository))
+}
+
+// triggerIndexKeys returns the keys of the buckets a trigger is added to; names and repositories are compared case insensitive
+// by the triggers, so they're lowercased, while regex patterns are only bucketed by value if they're a literal string
+func triggerIndexKeys(t *ZiplineeTrigger) []string {
+	switch {
+	case t.All != nil || t.Any != nil:
+		// 'all' and 'any' triggers can fire for the events of any of their sub-triggers
+		keys := []string{}
+		for _, leaf := range t.leafTriggers() {
+			keys = append(keys, triggerIndexKeys(leaf)...)
+		}
+		return keys
+	case t.Pipeline != nil:
+		return []string{triggerIndexKey("pipeline", strings.ToLower(t.Pipeline.Name))}
+	case t.Release != nil:
+		return []string{triggerIndexKey("release", strings.ToLower(t.Release.Name))}
+	case t.Git != nil:
+		return []string{triggerIndexKey("git", strings.ToLower(t.Git.Repository))}
+	case t.Docker != nil:
+		return []string{triggerIndexKey("docker", literalIndexKey(t.Docker.Image))}
+	case t.Cron != nil:
+		return cronTriggerIndexKeys(t.Cron)
+	case t.PubSub != nil:
+		return []string{triggerIndexKey("pubsub", literalIndexKey(t.PubSub.Topic))}
+	case t.Github != nil:
+		return []string{triggerIndexKey("github"), triggerIndexKey("github", strings.ToLower(t.Github.Repository))}
+	case t.Bitbucket != nil:
+		return []string{triggerIndexKey("bitbucket"), triggerIndexKey("bitbucket", strings.ToLower(t.Bitbucket.Repository))}
+	case t.Gitlab != nil:
+		return []string{triggerIndexKey("gitlab"), triggerIndexKey("gitlab", strings.ToLower(t.Gitlab.Repository))}
+	case t.Webhook != nil:
+		return []string{triggerIndexKey("webhook", strings.ToLower(t.Webhook.Name))}
+	case t.Manual != nil:
+		return []string{triggerIndexKey("manual")}
+	}
+
+	return nil
+}
+
+// cronTriggerIndexKeys buckets cron triggers by the minutes of the hour they fire at in their timezone
+func cronTriggerIndexKeys(c *ZiplineeCronTrigger) (keys []string) {
+	schedule, err := resolveHashedCronSchedule(c.Schedule, "")
+	if err != nil {
+		return nil
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil
+	}
+	spec, ok := sched.(*cron.SpecSchedule)
+	if !ok {
+		return []string{triggerIndexKey("cron", triggerIndexAnyKey)}
+	}
+
+	for minute := 0; minute < 60; minute++ {
+		if 1<<uint(minute)&spec.Minute > 0 {
+			keys = append(keys, triggerIndexKey("cron", c.Timezone, strconv.Itoa(minute)))
+		}
+	}
+
+	return
+}
+
+// literalIndexKey returns the pattern itself if it's a plain string, or the any key if it's a regex or negated pattern
+func literalIndexKey(pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(pattern, "=~") || strings.HasPrefix(pattern, "!~") || regexp.QuoteMeta(pattern) != pattern {
+		return triggerIndexAnyKey
+	}
+
+	return pattern
+}
+
+func triggerIndexKey(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+func appendTriggerIndexEntry(entries []*triggerIndexEntry, pipelineName string, triggerType TriggerType, targetName string, targetConcurrency *ZiplineeConcurrency, t *ZiplineeTrigger) []*triggerIndexEntry {
+	if t == nil {
+		return entries
+	}
+	t.ReplaceSelf(pipelineName)
+
+	match := ZiplineeTriggerMatch{
+		Type:        triggerType,
+		Trigger:     t,
+		Concurrency: resolveConcurrency(t, targetConcurrency, pipelineName, triggerType, targetName),
+	}
+	switch triggerType {
+	case TriggerTypeBuild:
+		match.BuildAction = t.BuildAction
+	case TriggerTypeRelease:
+		match.ReleaseAction = t.ReleaseAction
+	case TriggerTypeBot:
+		match.BotAction = t.BotAction
+	}
+
+	return append(entries, &triggerIndexEntry{
+		pipelineName: pipelineName,
+		match:        match,
+	})
+}
@@ -0,0 +1,340 @@
+package manifest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTriggerIndexTestManifest(t testing.TB, manifestString string) *ZiplineeManifest {
+	manifest, err := ReadManifest(GetDefaultManifestPreferences(), manifestString, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &manifest
+}
+
+const triggerIndexTestManifest = `
+triggers:
+- pipeline:
+    name: github.com/ziplineeci/ziplinee-ci-contracts
+- git:
+    repository: github.com/ziplineeci/ziplinee-ci-api
+- cron:
+    schedule: '30 9 * * *'
+    timezone: Europe/Amsterdam
+stages:
+  build:
+    image: golang
+releases:
+  production:
+    triggers:
+    - pipeline:
+        name: self
+        branch: main
+    stages:
+      deploy:
+        image: alpine
+bots:
+  pr-bot:
+    triggers:
+    - github:
+        events:
+        - pull_request
+    stages:
+      welcome:
+        image: alpine`
+
+func TestTriggerIndex(t *testing.T) {
+	t.Run("ReturnsTriggersFiringForPipelineEventAcrossPipelines", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api":     readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+			"github.com/ziplineeci/ziplinee-ci-builder": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+
+		// act
+		matches := index.Match(&ZiplineeEvent{
+			Pipeline: &ZiplineePipelineEvent{Event: "finished", Status: "succeeded", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-contracts", Branch: "main"},
+		})
+
+		if assert.Equal(t, 2, len(matches)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api", matches[0].PipelineName)
+			assert.Equal(t, TriggerTypeBuild, matches[0].Type)
+			assert.Equal(t, "master", matches[0].BuildAction.Branch)
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-builder", matches[1].PipelineName)
+		}
+	})
+
+	t.Run("ReplacesSelfWithPipelineName", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api":     readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+			"github.com/ziplineeci/ziplinee-ci-builder": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+
+		// act
+		matches := index.Match(&ZiplineeEvent{
+			Pipeline: &ZiplineePipelineEvent{Event: "finished", Status: "succeeded", RepoSource: "github.com", RepoOwner: "ZiplineeCI", RepoName: "ziplinee-ci-api", Branch: "main"},
+		})
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api", matches[0].PipelineName)
+			assert.Equal(t, TriggerTypeRelease, matches[0].Type)
+			assert.Equal(t, "production", matches[0].ReleaseAction.Target)
+		}
+	})
+
+	t.Run("DoesNotChangeManifestsPassedIn", func(t *testing.T) {
+
+		manifest := readTriggerIndexTestManifest(t, triggerIndexTestManifest)
+
+		// act
+		NewTriggerIndex(map[string]*ZiplineeManifest{"github.com/ziplineeci/ziplinee-ci-api": manifest})
+
+		assert.Equal(t, "self", manifest.Releases[0].Triggers[0].Pipeline.Name)
+	})
+
+	t.Run("ReturnsCronTriggersForMinuteInTheirTimezone", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+
+		// act
+		matches := index.Match(&ZiplineeEvent{Cron: &ZiplineeCronEvent{Time: time.Date(2019, 7, 8, 7, 30, 0, 0, time.UTC)}})
+		noMatches := index.Match(&ZiplineeEvent{Cron: &ZiplineeCronEvent{Time: time.Date(2019, 7, 8, 9, 30, 0, 0, time.UTC)}})
+
+		assert.Equal(t, 1, len(matches))
+		assert.Equal(t, 0, len(noMatches))
+	})
+
+	t.Run("ReturnsIntegrationTriggersOfAllPipelinesForEventWithoutRepository", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api":     readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+			"github.com/ziplineeci/ziplinee-ci-builder": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+
+		// act
+		forRepository := index.Match(&ZiplineeEvent{Github: &ZiplineeGithubEvent{Event: "pull_request", Repository: "github.com/ziplineeci/ziplinee-ci-builder"}})
+		withoutRepository := index.Match(&ZiplineeEvent{Github: &ZiplineeGithubEvent{Event: "pull_request"}})
+
+		if assert.Equal(t, 1, len(forRepository)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-builder", forRepository[0].PipelineName)
+			assert.Equal(t, "pr-bot", forRepository[0].BotAction.Bot)
+		}
+		assert.Equal(t, 2, len(withoutRepository))
+	})
+
+	t.Run("ReturnsTriggersWithRegexPatterns", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, `
+triggers:
+- pubsub:
+    project: my-project
+    topic: 'image-(pushed|deleted)'
+- pubsub:
+    project: my-project
+    topic: image-pushed
+stages:
+  build:
+    image: golang`),
+		})
+
+		// act
+		matches := index.Match(&ZiplineeEvent{PubSub: &ZiplineePubSubEvent{Project: "my-project", Topic: "image-pushed"}})
+
+		assert.Equal(t, 2, len(matches))
+	})
+
+	t.Run("ReplacesTriggersWhenPipelineIsAddedAgain", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+		assert.Equal(t, 5, index.Len())
+
+		// act
+		index.Add("github.com/ziplineeci/ziplinee-ci-api", readTriggerIndexTestManifest(t, `
+triggers:
+- git:
+    repository: github.com/ziplineeci/ziplinee-ci-api
+stages:
+  build:
+    image: golang`))
+
+		assert.Equal(t, 1, index.Len())
+		assert.Equal(t, 0, len(index.Match(&ZiplineeEvent{Cron: &ZiplineeCronEvent{Time: time.Date(2019, 7, 8, 7, 30, 0, 0, time.UTC)}})))
+		assert.Equal(t, 1, len(index.Match(&ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-api", Branch: "master"}})))
+	})
+
+	t.Run("RemovesTriggersOfPipeline", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api":     readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+			"github.com/ziplineeci/ziplinee-ci-builder": readTriggerIndexTestManifest(t, triggerIndexTestManifest),
+		})
+
+		// act
+		index.Remove("github.com/ziplineeci/ziplinee-ci-api")
+
+		assert.Equal(t, 5, index.Len())
+		matches := index.Match(&ZiplineeEvent{Github: &ZiplineeGithubEvent{Event: "pull_request"}})
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-builder", matches[0].PipelineName)
+		}
+	})
+
+	t.Run("ReturnsMatchesWithResolvedConcurrencyOfRelease", func(t *testing.T) {
+
+		manifest := readTriggerIndexTestManifest(t, triggerIndexTestManifest)
+		manifest.Releases[0].Concurrency = &ZiplineeConcurrency{MaxInFlight: 1, CancelInProgress: true}
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{"github.com/ziplineeci/ziplinee-ci-api": manifest})
+
+		// act
+		matches := index.Match(&ZiplineeEvent{
+			Pipeline: &ZiplineePipelineEvent{Event: "finished", Status: "succeeded", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Branch: "main"},
+		})
+
+		if assert.Equal(t, 1, len(matches)) {
+			assert.Equal(t, &ZiplineeResolvedConcurrency{
+				Group:            "github.com/ziplineeci/ziplinee-ci-api/release/production",
+				MaxInFlight:      1,
+				CancelInProgress: true,
+			}, matches[0].Concurrency)
+		}
+	})
+
+	t.Run("ReturnsCompositeTriggersForEventsOfTheirSubTriggers", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, `
+triggers:
+- any:
+    triggers:
+    - git:
+        repository: github.com/ziplineeci/ziplinee-ci-contracts
+    - cron:
+        schedule: '0 6 * * *'
+stages:
+  build:
+    image: golang`),
+		})
+
+		// act
+		gitMatches := index.Match(&ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-contracts", Branch: "main"}})
+		cronMatches := index.Match(&ZiplineeEvent{Cron: &ZiplineeCronEvent{Time: time.Date(2024, 3, 5, 6, 0, 0, 0, time.UTC)}})
+		otherMatches := index.Match(&ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-web", Branch: "main"}})
+
+		assert.Equal(t, 1, len(gitMatches))
+		assert.Equal(t, 1, len(cronMatches))
+		assert.Equal(t, 0, len(otherMatches))
+	})
+
+	t.Run("ReturnsAllTriggersForLastEventInStream", func(t *testing.T) {
+
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{
+			"github.com/ziplineeci/ziplinee-ci-api": readTriggerIndexTestManifest(t, `
+triggers:
+- all:
+    triggers:
+    - git:
+        repository: github.com/ziplineeci/ziplinee-ci-contracts
+    - git:
+        repository: github.com/ziplineeci/ziplinee-ci-foundation
+stages:
+  build:
+    image: golang`),
+		})
+		contracts := &ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-contracts", Branch: "main"}}
+		foundation := &ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-foundation", Branch: "main"}}
+
+		// act
+		matches := index.MatchEvents([]*ZiplineeEvent{contracts, foundation})
+
+		assert.Equal(t, 1, len(matches))
+		assert.Equal(t, 0, len(index.Match(foundation)))
+		assert.Equal(t, 0, len(index.MatchEvents([]*ZiplineeEvent{foundation, nil})))
+	})
+
+	t.Run("ReturnsSameMatchesAsManifestMatchingTriggers", func(t *testing.T) {
+
+		manifest := readTriggerIndexTestManifest(t, triggerIndexTestManifest)
+		index := NewTriggerIndex(map[string]*ZiplineeManifest{"github.com/ziplineeci/ziplinee-ci-api": manifest})
+		event := &ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/ziplinee-ci-api", Branch: "master"}}
+
+		// act
+		matches := index.Match(event)
+
+		expected := manifest.MatchingTriggers("github.com", "ziplineeci", "ziplinee-ci-api", event)
+		if assert.Equal(t, len(expected), len(matches)) {
+			for i := range expected {
+				assert.Equal(t, expected[i].Type, matches[i].Type)
+				assert.Equal(t, *expected[i].Trigger, *matches[i].Trigger)
+			}
+		}
+	})
+
+	t.Run("IsSafeForConcurrentReadsAndWrites", func(t *testing.T) {
+
+		index := NewTriggerIndex(nil)
+		event := &ZiplineeEvent{Github: &ZiplineeGithubEvent{Event: "pull_request"}}
+
+		// act
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				index.Add(fmt.Sprintf("github.com/ziplineeci/pipeline-%v", i), readTriggerIndexTestManifest(t, triggerIndexTestManifest))
+			}(i)
+			go func() {
+				defer wg.Done()
+				index.Match(event)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 10, len(index.Match(event)))
+	})
+}
+
+func BenchmarkTriggerIndex(b *testing.B) {
+
+	manifest := readTriggerIndexTestManifest(b, triggerIndexTestManifest)
+	manifests := map[string]*ZiplineeManifest{}
+	for i := 0; i < 2000; i++ {
+		manifests[fmt.Sprintf("github.com/ziplineeci/pipeline-%v", i)] = manifest
+	}
+	event := &ZiplineeEvent{Git: &ZiplineeGitEvent{Event: "push", Repository: "github.com/ziplineeci/pipeline-42", Branch: "master"}}
+
+	b.Run("Match", func(b *testing.B) {
+		index := NewTriggerIndex(manifests)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			index.Match(event)
+		}
+	})
+
+	b.Run("MatchingTriggersPerManifest", func(b *testing.B) {
+		copies := map[string]*ZiplineeManifest{}
+		for name, m := range manifests {
+			c := m.DeepCopy()
+			copies[name] = &c
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for name, m := range copies {
+				m.MatchingTriggers("github.com", "ziplineeci", name[len("github.com/ziplineeci/"):], event)
+			}
+		}
+	})
+}
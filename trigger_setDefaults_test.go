@@ -93,6 +93,46 @@ func TestZiplineeGitTriggerSetDefaults(t *testing.T) {
 
 		assert.Equal(t, "master|main", trigger.Branch)
 	})
+
+	t.Run("DoesNotSetBranchForTagEvent", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event: "tag",
+		}
+
+		// act
+		trigger.SetDefaults()
+
+		assert.Equal(t, "", trigger.Branch)
+	})
+}
+
+func TestZiplineeDockerTriggerSetDefaults(t *testing.T) {
+	t.Run("SetsEventToPushIfEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "",
+		}
+
+		// act
+		trigger.SetDefaults()
+
+		assert.Equal(t, "push", trigger.Event)
+	})
+}
+
+func TestZiplineeGitlabTriggerSetDefaults(t *testing.T) {
+	t.Run("SetsRepositoryToSelfIfEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeGitlabTrigger{
+			Repository: "",
+		}
+
+		// act
+		trigger.SetDefaults()
+
+		assert.Equal(t, "self", trigger.Repository)
+	})
 }
 
 func TestZiplineeTriggerBuildActionSetDefaults(t *testing.T) {
@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -76,6 +77,26 @@ func TestZiplineeTriggerValidate(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 
+	t.Run("ReturnsErrorIfGitlabAndGithubAreBothSet", func(t *testing.T) {
+
+		trigger := ZiplineeTrigger{
+			Github: &ZiplineeGithubTrigger{
+				Events: []string{"push"},
+			},
+			Gitlab: &ZiplineeGitlabTrigger{
+				Events: []string{"Push Hook"},
+			},
+			BotAction: &ZiplineeTriggerBotAction{
+				Bot: "any-bot",
+			},
+		}
+
+		// act
+		err := trigger.Validate(TriggerTypeBot, "any-bot")
+
+		assert.NotNil(t, err)
+	})
+
 	t.Run("ReturnsErrorIfAllActionsAreEmpty", func(t *testing.T) {
 
 		trigger := ZiplineeTrigger{
@@ -228,6 +249,22 @@ func TestZiplineePipelineTriggerValidate(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("ReturnsErrorIfVersionIsInvalidRange", func(t *testing.T) {
+
+		trigger := ZiplineePipelineTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-manifest",
+			Branch:  "main",
+			Version: ">=2.0 <three",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
 }
 
 func TestZiplineeReleaseTriggerValidate(t *testing.T) {
@@ -320,6 +357,38 @@ func TestZiplineeReleaseTriggerValidate(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("ReturnsNoErrorIfVersionIsValidRange", func(t *testing.T) {
+
+		trigger := ZiplineeReleaseTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-manifest",
+			Target:  "staging",
+			Version: "^2.1 stable",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfVersionIsInvalidRegex", func(t *testing.T) {
+
+		trigger := ZiplineeReleaseTrigger{
+			Event:   "finished",
+			Status:  "succeeded",
+			Name:    "github.com/ziplineeci/ziplinee-ci-manifest",
+			Target:  "staging",
+			Version: "=~[0-9",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
 }
 
 func TestZiplineeGitTriggerValidate(t *testing.T) {
@@ -361,6 +430,433 @@ func TestZiplineeGitTriggerValidate(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("ReturnsErrorIfEventIsUnknown", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "merge",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForTagEventWithTag", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "tag",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Tag:        `v\d+\.\d+\.\d+`,
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfTagIsInvalidRegex", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "tag",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Tag:        "v(",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfTagIsGlob", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "tag",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Tag:        "v*",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "v.*")
+		}
+	})
+
+	t.Run("ReturnsNoErrorIfTagUsesStarAsRegex", func(t *testing.T) {
+
+		for _, tag := range []string{`v.*`, `v[0-9]*`, `v(\d+\.)*\d+`, `v\d*`, `release-\**`, `v[*]`} {
+			trigger := ZiplineeGitTrigger{
+				Event:      "tag",
+				Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+				Tag:        tag,
+			}
+
+			// act
+			err := trigger.Validate()
+
+			assert.Nil(t, err, tag)
+		}
+	})
+
+	t.Run("ReturnsErrorIfTagIsSetForPushEvent", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "push",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Branch:     "main",
+			Tag:        "v.*",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfBranchIsSetForPullRequestEvent", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "pull_request",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Branch:     "main",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForPullRequestEventWithSourceAndTargetBranch", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:        "pull_request",
+			Repository:   "github.com/ziplineeci/ziplinee-ci-manifest",
+			SourceBranch: "feature/.+",
+			TargetBranch: "main",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfPathsGlobIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:      "push",
+			Repository: "github.com/ziplineeci/ziplinee-ci-manifest",
+			Paths:      []string{"services/[api/**"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		var manifestError *ManifestError
+		if assert.True(t, errors.As(err, &manifestError)) {
+			assert.Equal(t, "paths[0]", manifestError.Path)
+		}
+	})
+
+	t.Run("ReturnsNoErrorIfPathsAndPathsIgnoreAreValid", func(t *testing.T) {
+
+		trigger := ZiplineeGitTrigger{
+			Event:       "push",
+			Repository:  "github.com/ziplineeci/ziplinee-ci-manifest",
+			Paths:       []string{"services/api/**"},
+			PathsIgnore: []string{"**/*.md"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeDockerTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfImageIsEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		trigger := ZiplineeDockerTrigger{
+			Event: "push",
+			Image: "ziplinee/ziplinee-ci-api",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeGithubTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfEventsAreEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeGithubTrigger{
+			Events: []string{},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfFilterUsesAttribute", func(t *testing.T) {
+
+		trigger := ZiplineeGithubTrigger{
+			Events: []string{"issue_comment"},
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type", Pattern: "image-pushed"},
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfEventGlobIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeGithubTrigger{
+			Events: []string{"pull_request[*"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		trigger := ZiplineeGithubTrigger{
+			Events: []string{"pull_request*", "=~ issue_.+"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineePubSubTriggerValidate(t *testing.T) {
+	t.Run("ReturnsNoErrorIfFilterUsesAttribute", func(t *testing.T) {
+
+		trigger := ZiplineePubSubTrigger{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type", Pattern: "image-pushed"},
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfFilterIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineePubSubTrigger{
+			Project: "my-project",
+			Topic:   "my-topic",
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type"},
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestZiplineeBitbucketTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfEventsAreEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeBitbucketTrigger{
+			Events: []string{},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfEventRegexIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeBitbucketTrigger{
+			Events: []string{"=~ pullrequest:(created"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		trigger := ZiplineeBitbucketTrigger{
+			Events: []string{"pullrequest:*"},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeGitlabTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfEventsAreEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeGitlabTrigger{
+			Events: []string{},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		trigger := ZiplineeGitlabTrigger{
+			Events:     []string{"Push Hook", "Merge Request Hook"},
+			Repository: "self",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeWebhookTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfNameIsEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeWebhookTrigger{
+			Name: "",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfNameContainsInvalidCharacters", func(t *testing.T) {
+
+		trigger := ZiplineeWebhookTrigger{
+			Name: "my webhook/1",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfHeaderPatternIsInvalidRegex", func(t *testing.T) {
+
+		trigger := ZiplineeWebhookTrigger{
+			Name: "jira",
+			Headers: map[string]string{
+				"X-Event-Type": "issue_(created",
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfFilterUsesAttribute", func(t *testing.T) {
+
+		trigger := ZiplineeWebhookTrigger{
+			Name: "jira",
+			Filters: []*ZiplineeTriggerFilter{
+				{Attribute: "type", Pattern: "issue"},
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfValid", func(t *testing.T) {
+
+		trigger := ZiplineeWebhookTrigger{
+			Name: "jira",
+			Headers: map[string]string{
+				"X-Event-Type": "issue_(created|updated)",
+			},
+			Filters: []*ZiplineeTriggerFilter{
+				{Path: "issue.fields.project.key", Pattern: "ZCI"},
+			},
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestZiplineeManualTriggerValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfUserRegexIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeManualTrigger{
+			User: "(.+@server.com",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfUserIsEmpty", func(t *testing.T) {
+
+		trigger := ZiplineeManualTrigger{}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
 }
 
 func TestZiplineeCronTriggerValidate(t *testing.T) {
@@ -399,4 +895,67 @@ func TestZiplineeCronTriggerValidate(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("ReturnsNoErrorIfScheduleHasHashedTokens", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "H H(1-4) * * H",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfHashedTokenIsInvalid", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "H(30-10) * * * *",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfTimezoneIsUnknown", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "0 9 * * 1-5",
+			Timezone: "Europe/Atlantis",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfTimezoneIsLocal", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "0 9 * * 1-5",
+			Timezone: "Local",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorIfTimezoneIsValid", func(t *testing.T) {
+
+		trigger := ZiplineeCronTrigger{
+			Schedule: "0 9 * * 1-5",
+			Timezone: "Europe/Amsterdam",
+		}
+
+		// act
+		err := trigger.Validate()
+
+		assert.Nil(t, err)
+	})
 }
@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"strings"
+)
+
+type ValidationSeverity string
+
+const (
+	ValidationSeverityError   ValidationSeverity = "error"
+	ValidationSeverityWarning ValidationSeverity = "warning"
+)
+
+type ValidationCode string
+
+const (
+	ValidationCodeBuilderInvalid      ValidationCode = "builder-invalid"
+	ValidationCodeLabelInvalid        ValidationCode = "label-invalid"
+	ValidationCodeStagesMissing       ValidationCode = "stages-missing"
+	ValidationCodeStageInvalid        ValidationCode = "stage-invalid"
+	ValidationCodeTriggerInvalid      ValidationCode = "trigger-invalid"
+	ValidationCodeInputInvalid        ValidationCode = "input-invalid"
+	ValidationCodeConcurrencyInvalid  ValidationCode = "concurrency-invalid"
+	ValidationCodeTemplateInvalid     ValidationCode = "template-invalid"
+	ValidationCodeWhenInvalid         ValidationCode = "when-invalid"
+	ValidationCodeWhenUnknownVariable ValidationCode = "when-unknown-variable"
+	ValidationCodeReadinessDeprecated ValidationCode = "readiness-deprecated"
+)
+
+// ValidationProblem is a single error or warning found while validating the manifest
+type ValidationProblem struct {
+	Severity ValidationSeverity
+	Code     ValidationCode
+	*ManifestError
+}
+
+// ValidationResult collects all errors and warnings found while validating the manifest
+type ValidationResult struct {
+	Problems []*ValidationProblem
+}
+
+// Errors returns all problems with severity error
+func (r *ValidationResult) Errors() (problems []*ValidationProblem) {
+	return r.filter(ValidationSeverityError)
+}
+
+// Warnings returns all problems with severity warning
+func (r *ValidationResult) Warnings() (problems []*ValidationProblem) {
+	return r.filter(ValidationSeverityWarning)
+}
+
+// HasErrors indicates whether any problem with severity error has been found
+func (r *ValidationResult) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Err returns the result as an error if it has any errors, otherwise nil
+func (r *ValidationResult) Err() error {
+	if !r.HasErrors() {
+		return nil
+	}
+
+	return r
+}
+
+// Error returns the messages of all errors, one per line
+func (r *ValidationResult) Error() string {
+	messages := []string{}
+	for _, p := range r.Errors() {
+		messages = append(messages, p.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors so errors.As can be used to get at the first ManifestError
+func (r *ValidationResult) Unwrap() []error {
+	errors := []error{}
+	for _, p := range r.Errors() {
+		errors = append(errors, p.ManifestError)
+	}
+
+	return errors
+}
+
+func (r *ValidationResult) filter(severity ValidationSeverity) (problems []*ValidationProblem) {
+	for _, p := range r.Problems {
+		if p.Severity == severity {
+			problems = append(problems, p)
+		}
+	}
+
+	return
+}
+
+func (r *ValidationResult) addError(code ValidationCode, path string, err error) {
+	r.add(ValidationSeverityError, code, path, err)
+}
+
+func (r *ValidationResult) addWarning(code ValidationCode, path string, err error) {
+	r.add(ValidationSeverityWarning, code, path, err)
+}
+
+func (r *ValidationResult) add(severity ValidationSeverity, code ValidationCode, path string, err error) {
+	if err == nil {
+		return
+	}
+
+	r.Problems = append(r.Problems, &ValidationProblem{
+		Severity:      severity,
+		Code:          code,
+		ManifestError: wrapManifestError(path, err).(*ManifestError),
+	})
+}
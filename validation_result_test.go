@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationResult(t *testing.T) {
+	t.Run("ErrReturnsNilIfThereAreOnlyWarnings", func(t *testing.T) {
+
+		result := &ValidationResult{}
+		result.addWarning(ValidationCodeReadinessDeprecated, "stages.build.services[0].readiness", fmt.Errorf("deprecated"))
+
+		// act
+		err := result.Err()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result.Warnings()))
+	})
+
+	t.Run("ErrReturnsAllErrorMessagesOnePerLine", func(t *testing.T) {
+
+		result := &ValidationResult{}
+		result.addError(ValidationCodeStageInvalid, "stages.build", fmt.Errorf("Stage build has no image set"))
+		result.addWarning(ValidationCodeReadinessDeprecated, "stages.build.services[0].readiness", fmt.Errorf("deprecated"))
+		result.addError(ValidationCodeStageInvalid, "stages.bake", fmt.Errorf("Stage bake has no image set"))
+
+		// act
+		err := result.Err()
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Stage build has no image set\nStage bake has no image set", err.Error())
+	})
+
+	t.Run("ErrUnwrapsToFirstManifestError", func(t *testing.T) {
+
+		result := &ValidationResult{}
+		result.addError(ValidationCodeStageInvalid, "stages.build", fmt.Errorf("Stage build has no image set"))
+		result.addError(ValidationCodeStageInvalid, "stages.bake", fmt.Errorf("Stage bake has no image set"))
+
+		// act
+		err := result.Err()
+
+		var manifestError *ManifestError
+		assert.True(t, errors.As(err, &manifestError))
+		assert.Equal(t, "stages.build", manifestError.Path)
+	})
+
+	t.Run("AddIgnoresNilErrors", func(t *testing.T) {
+
+		result := &ValidationResult{}
+
+		// act
+		result.addError(ValidationCodeStageInvalid, "stages.build", nil)
+
+		assert.Equal(t, 0, len(result.Problems))
+	})
+}
@@ -0,0 +1,338 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// versionConstraintStable is the term in a version constraint that only matches versions without prerelease label, i.e. >=2.0.0 stable
+const versionConstraintStable = "stable"
+
+// semanticVersion is a parsed semver version; build metadata is dropped since it doesn't affect precedence
+type semanticVersion struct {
+	major      uint64
+	minor      uint64
+	patch      uint64
+	prerelease []string
+}
+
+// versionComparator compares a version against the version in the comparator, or checks it's stable for operator stable
+type versionComparator struct {
+	operator string
+	version  semanticVersion
+}
+
+// versionConstraintMatch indicates whether the version satisfies the constraint; an empty constraint matches any version, a constraint
+// starting with =~ or !~ is matched as regex and anything else as semver range like '>=2.0.0 <3.0.0 || ^4.1 stable'
+func versionConstraintMatch(constraint, version string) (bool, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return true, nil
+	}
+	if strings.HasPrefix(constraint, "=~") || strings.HasPrefix(constraint, "!~") {
+		return regexMatch(constraint, version)
+	}
+
+	// a bare value is always parsed as range, so point regexes like 1\.2\..* without =~ prefix to it
+	alternatives, err := parseVersionRange(constraint)
+	if err != nil {
+		return false, fmt.Errorf("%v; to match the version as regex prefix it with =~, like =~%v", err, constraint)
+	}
+
+	// versions that aren't semver, like custom versions, never satisfy a range
+	v, err := parseSemanticVersion(version)
+	if err != nil {
+		return false, nil
+	}
+
+	for _, comparators := range alternatives {
+		if v.satisfies(comparators) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// validateVersionConstraint checks whether the constraint is a valid regex or semver range
+func validateVersionConstraint(constraint string) error {
+	_, err := versionConstraintMatch(constraint, "")
+	return err
+}
+
+// parseVersionRange parses a semver range into alternatives separated by ||, each with comparators that all need to be satisfied
+func parseVersionRange(constraint string) (alternatives [][]versionComparator, err error) {
+	for _, alternative := range strings.Split(constraint, "||") {
+		terms := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("Version constraint %v has an empty alternative", constraint)
+		}
+
+		comparators := []versionComparator{}
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// allow a space between the operator and version, like >= 2.0.0
+			if strings.Trim(term, "<>=!~^") == "" && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+
+			termComparators, err := parseVersionTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("Version constraint %v is invalid: %v", constraint, err)
+			}
+			comparators = append(comparators, termComparators...)
+		}
+		alternatives = append(alternatives, comparators)
+	}
+
+	return
+}
+
+// parseVersionTerm turns a single term like >=1.2, ~1.2.3, ^0.4 or 2.x into comparators with full versions
+func parseVersionTerm(term string) (comparators []versionComparator, err error) {
+	if term == versionConstraintStable {
+		return []versionComparator{{operator: versionConstraintStable}}, nil
+	}
+
+	operator := ""
+	for _, o := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(term, o) {
+			operator = o
+			break
+		}
+	}
+
+	lower, specified, err := parsePartialVersion(strings.TrimPrefix(term, operator))
+	if err != nil {
+		return nil, err
+	}
+
+	if specified == 0 {
+		switch operator {
+		case "", "=", ">=", "<=":
+			// a wildcard matches any version
+			return []versionComparator{}, nil
+		}
+		return nil, fmt.Errorf("Operator %v can't be used with wildcard version %v", operator, term)
+	}
+
+	// the exclusive upper bound of a partial version, i.e. 1.3.0-0 for 1.2, so prereleases of 1.3.0 don't match either
+	upper := lower.bump(specified - 1)
+
+	switch operator {
+	case "", "=":
+		if specified == 3 {
+			return []versionComparator{{operator: "=", version: lower}}, nil
+		}
+		return []versionComparator{{operator: ">=", version: lower}, {operator: "<", version: upper}}, nil
+
+	case "!=":
+		if specified != 3 {
+			return nil, fmt.Errorf("Operator != needs a full version like 1.2.3, not %v", term)
+		}
+		return []versionComparator{{operator: "!=", version: lower}}, nil
+
+	case ">=":
+		return []versionComparator{{operator: ">=", version: lower}}, nil
+
+	case ">":
+		if specified == 3 {
+			return []versionComparator{{operator: ">", version: lower}}, nil
+		}
+		return []versionComparator{{operator: ">=", version: upper}}, nil
+
+	case "<":
+		// exclude prereleases of the version itself, so <3.0.0 doesn't match 3.0.0-beta
+		if len(lower.prerelease) == 0 {
+			lower.prerelease = []string{"0"}
+		}
+		return []versionComparator{{operator: "<", version: lower}}, nil
+
+	case "<=":
+		if specified == 3 {
+			return []versionComparator{{operator: "<=", version: lower}}, nil
+		}
+		return []versionComparator{{operator: "<", version: upper}}, nil
+
+	case "~":
+		// ~1.2.3 allows patch updates, ~1 allows minor updates
+		if specified == 1 {
+			upper = lower.bump(0)
+		} else {
+			upper = lower.bump(1)
+		}
+		return []versionComparator{{operator: ">=", version: lower}, {operator: "<", version: upper}}, nil
+
+	case "^":
+		// ^1.2.3 allows updates that don't change the left-most non-zero part
+		switch {
+		case lower.major > 0 || specified == 1:
+			upper = lower.bump(0)
+		case lower.minor > 0 || specified == 2:
+			upper = lower.bump(1)
+		default:
+			upper = lower.bump(2)
+		}
+		return []versionComparator{{operator: ">=", version: lower}, {operator: "<", version: upper}}, nil
+	}
+
+	return nil, fmt.Errorf("Term %v is not a valid version, operator with version or %v", term, versionConstraintStable)
+}
+
+// parsePartialVersion parses versions like 1, 1.2, 1.2.x or 1.2.3-beta.1, returning how many of the numeric parts are specified
+func parsePartialVersion(value string) (version semanticVersion, specified int, err error) {
+	numeric := strings.TrimPrefix(value, "v")
+	if i := strings.Index(numeric, "+"); i >= 0 {
+		numeric = numeric[:i]
+	}
+	if i := strings.Index(numeric, "-"); i >= 0 {
+		version.prerelease = strings.Split(numeric[i+1:], ".")
+		numeric = numeric[:i]
+		for _, identifier := range version.prerelease {
+			if identifier == "" {
+				return version, 0, fmt.Errorf("Version %v has an empty prerelease identifier", value)
+			}
+		}
+	}
+	if numeric == "" {
+		return version, 0, fmt.Errorf("Version is missing")
+	}
+
+	parts := strings.Split(numeric, ".")
+	if len(parts) > 3 {
+		return version, 0, fmt.Errorf("Version %v has more than 3 parts", value)
+	}
+
+	numbers := []*uint64{&version.major, &version.minor, &version.patch}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, 0, fmt.Errorf("Version %v has non-numeric part %v", value, part)
+		}
+		*numbers[i] = n
+		specified++
+	}
+	if len(version.prerelease) > 0 && specified < 3 {
+		return version, 0, fmt.Errorf("Version %v can only have a prerelease label if all 3 parts are set", value)
+	}
+
+	return version, specified, nil
+}
+
+// parseSemanticVersion parses a full version like 1.2.3 or 1.2.3-feature-branch.4, allowing a v prefix
+func parseSemanticVersion(value string) (version semanticVersion, err error) {
+	version, specified, err := parsePartialVersion(value)
+	if err != nil {
+		return version, err
+	}
+	if specified != 3 {
+		return version, fmt.Errorf("Version %v is not a full semantic version", value)
+	}
+
+	return version, nil
+}
+
+// bump returns the lowest version after all versions with the same parts up to index, i.e. 1.3.0-0 for part 1 of 1.2.3
+func (v semanticVersion) bump(part int) semanticVersion {
+	switch part {
+	case 0:
+		return semanticVersion{major: v.major + 1, prerelease: []string{"0"}}
+	case 1:
+		return semanticVersion{major: v.major, minor: v.minor + 1, prerelease: []string{"0"}}
+	}
+
+	return semanticVersion{major: v.major, minor: v.minor, patch: v.patch + 1, prerelease: []string{"0"}}
+}
+
+// compare returns -1, 0 or 1 depending on the precedence of v compared to o as defined by semver
+func (v semanticVersion) compare(o semanticVersion) int {
+	for _, parts := range [][2]uint64{{v.major, o.major}, {v.minor, o.minor}, {v.patch, o.patch}} {
+		if parts[0] != parts[1] {
+			if parts[0] < parts[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// a version without prerelease has higher precedence than one with
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrereleaseIdentifiers(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.prerelease) < len(o.prerelease):
+		return -1
+	case len(v.prerelease) > len(o.prerelease):
+		return 1
+	}
+
+	return 0
+}
+
+// comparePrereleaseIdentifiers compares numeric identifiers numerically and others lexically, with numeric ones lower than others
+func comparePrereleaseIdentifiers(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		if an == bn {
+			return 0
+		}
+		if an < bn {
+			return -1
+		}
+		return 1
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// satisfies indicates whether the version satisfies all comparators
+func (v semanticVersion) satisfies(comparators []versionComparator) bool {
+	for _, c := range comparators {
+		var satisfied bool
+		switch c.operator {
+		case versionConstraintStable:
+			satisfied = len(v.prerelease) == 0
+		case "=":
+			satisfied = v.compare(c.version) == 0
+		case "!=":
+			satisfied = v.compare(c.version) != 0
+		case ">":
+			satisfied = v.compare(c.version) > 0
+		case ">=":
+			satisfied = v.compare(c.version) >= 0
+		case "<":
+			satisfied = v.compare(c.version) < 0
+		case "<=":
+			satisfied = v.compare(c.version) <= 0
+		}
+		if !satisfied {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionConstraintMatch(t *testing.T) {
+
+	testCases := []struct {
+		constraint string
+		version    string
+		match      bool
+	}{
+		{"", "1.2.3-feature-branch", true},
+		{"=~\\d+\\.\\d+\\.\\d+", "1.2.3", true},
+		{"=~\\d+\\.\\d+\\.\\d+", "1.2.3-feature-branch", false},
+		{"!~.*-.*", "1.2.3-feature-branch", false},
+		{">=2.0.0 <3.0.0", "2.4.1", true},
+		{">=2.0.0 <3.0.0", "3.0.0", false},
+		{">=2.0.0 <3.0.0", "3.0.0-beta", false},
+		{">=2.0.0 <3.0.0", "1.9.9", false},
+		{">= 2.0.0, < 3.0.0", "2.0.0", true},
+		{">=2.0.0 <3.0.0", "2.1.0-feature-branch", true},
+		{">=2.0.0 <3.0.0 stable", "2.1.0-feature-branch", false},
+		{"stable", "2.1.0", true},
+		{"stable", "2.1.0-feature-branch", false},
+		{"stable", "v2.1.0+build.5", true},
+		{"1.2.3", "1.2.3", true},
+		{"=1.2.3", "1.2.4", false},
+		{"!=1.2.3", "1.2.4", true},
+		{"1.2", "1.2.9", true},
+		{"1.2.x", "1.3.0-0", false},
+		{"2.*", "2.9.0", true},
+		{"*", "0.0.1-alpha", true},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<=1.2", "1.3.0-alpha", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^1.0 || ^3.0", "3.1.0", true},
+		{"^1.0 || ^3.0", "2.1.0", false},
+		{">=1.0.0-alpha.2", "1.0.0-alpha.10", true},
+		{">=1.0.0-alpha.2", "1.0.0-alpha.1", false},
+		{">1.0.0-alpha", "1.0.0-alpha.1", true},
+		{">1.0.0-alpha.beta", "1.0.0-beta", true},
+		{">1.0.0-1", "1.0.0-alpha", true},
+		{">=1.0.0", "20240101.1", false},
+		{">=1.0.0", "", false},
+		{"=~1\\.2\\..*", "1.2.3", true},
+		{"=~1\\.2\\..*", "1.3.0", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+"_"+tc.version, func(t *testing.T) {
+
+			// act
+			match, err := versionConstraintMatch(tc.constraint, tc.version)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+}
+
+func TestValidateVersionConstraint(t *testing.T) {
+	t.Run("ReturnsNoErrorForValidRange", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint(">=2.0.0 <3.0.0 stable || ^4.1")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForValidRegex", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint("=~2\\..+")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorForInvalidRegex", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint("=~2\\.(")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForNonNumericVersion", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint(">=two")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForUnknownTerm", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint(">=2.0.0 prerelease")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForEmptyAlternative", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint(">=2.0.0 ||")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForOperatorWithWildcard", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint(">*")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForPrereleaseOnPartialVersion", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint("~1.2-beta")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForRegexWithoutPrefix", func(t *testing.T) {
+
+		// act
+		err := validateVersionConstraint("1\\.2\\..*")
+
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "=~1\\.2\\..*")
+		}
+	})
+}
@@ -0,0 +1,503 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhenContext holds the variables available to stage and service when expressions:
+//
+//	status   the status of the build or release so far, succeeded or failed
+//	branch   the branch being built or released
+//	server   the ci server running the stage, for example ziplinee
+//	action   the release action being executed, empty for builds
+//	labels   the manifest labels, used as labels.app or labels['app']
+//	env      the environment variables, used as env.VAR_A or env['VAR_A']
+type WhenContext struct {
+	Status string
+	Branch string
+	Server string
+	Action string
+	Labels map[string]string
+	Env    map[string]string
+}
+
+// WhenExpression is a parsed when expression; it supports the operators ==, !=, =~, !~, &&, || and ! and parentheses for grouping
+type WhenExpression struct {
+	Expression string
+	Root       WhenNode
+}
+
+// WhenNode is a node in the abstract syntax tree of a when expression
+type WhenNode interface {
+	String() string
+	evaluate(context WhenContext) (interface{}, error)
+}
+
+// WhenLogicalNode combines two boolean operands with && or ||
+type WhenLogicalNode struct {
+	Operator string
+	Left     WhenNode
+	Right    WhenNode
+}
+
+// WhenNotNode negates a boolean operand
+type WhenNotNode struct {
+	Operand WhenNode
+}
+
+// WhenComparisonNode compares two operands with ==, !=, =~ or !~
+type WhenComparisonNode struct {
+	Operator string
+	Left     WhenNode
+	Right    WhenNode
+}
+
+// WhenVariableNode refers to a variable from the WhenContext, with Key set for labels and env
+type WhenVariableNode struct {
+	Name string
+	Key  string
+}
+
+// WhenLiteralNode is a quoted string or the boolean true or false
+type WhenLiteralNode struct {
+	Value interface{}
+}
+
+// ParseWhenExpression parses a when expression into its abstract syntax tree
+func ParseWhenExpression(expression string) (*WhenExpression, error) {
+
+	tokens, err := tokenizeWhenExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &whenParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != whenTokenEnd {
+		return nil, fmt.Errorf("Unexpected %v at position %v in when expression", p.peek(), p.peek().position)
+	}
+
+	return &WhenExpression{
+		Expression: expression,
+		Root:       root,
+	}, nil
+}
+
+// UnknownVariables returns the variables in the expression that aren't in the WhenContext, in order of first use; they parse so
+// servers can provide more variables, but are more likely a typo like stauts
+func (e *WhenExpression) UnknownVariables() (names []string) {
+	found := map[string]bool{}
+	var walk func(node WhenNode)
+	walk = func(node WhenNode) {
+		switch n := node.(type) {
+		case *WhenLogicalNode:
+			walk(n.Left)
+			walk(n.Right)
+		case *WhenComparisonNode:
+			walk(n.Left)
+			walk(n.Right)
+		case *WhenNotNode:
+			walk(n.Operand)
+		case *WhenVariableNode:
+			if !isKnownWhenVariable(n.Name) && !found[n.Name] {
+				found[n.Name] = true
+				names = append(names, n.Name)
+			}
+		}
+	}
+	walk(e.Root)
+
+	return
+}
+
+// Evaluate returns whether the when expression holds for the provided context
+func (e *WhenExpression) Evaluate(context WhenContext) (bool, error) {
+	value, err := e.Root.evaluate(context)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("When expression %v does not evaluate to true or false", e.Expression)
+	}
+
+	return result, nil
+}
+
+func (n *WhenLogicalNode) String() string {
+	return fmt.Sprintf("(%v %v %v)", n.Left, n.Operator, n.Right)
+}
+
+func (n *WhenLogicalNode) evaluate(context WhenContext) (interface{}, error) {
+	left, err := evaluateWhenBool(n.Left, context)
+	if err != nil {
+		return nil, err
+	}
+
+	// short-circuit like in any other language
+	if n.Operator == "&&" && !left {
+		return false, nil
+	}
+	if n.Operator == "||" && left {
+		return true, nil
+	}
+
+	return evaluateWhenBool(n.Right, context)
+}
+
+func (n *WhenNotNode) String() string {
+	return fmt.Sprintf("!%v", n.Operand)
+}
+
+func (n *WhenNotNode) evaluate(context WhenContext) (interface{}, error) {
+	value, err := evaluateWhenBool(n.Operand, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return !value, nil
+}
+
+func (n *WhenComparisonNode) String() string {
+	return fmt.Sprintf("(%v %v %v)", n.Left, n.Operator, n.Right)
+}
+
+func (n *WhenComparisonNode) evaluate(context WhenContext) (interface{}, error) {
+	left, err := n.Left.evaluate(context)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.Right.evaluate(context)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Operator {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	}
+
+	// =~ and !~ match the full value against the pattern, the same as trigger filters do
+	value, valueIsString := left.(string)
+	pattern, patternIsString := right.(string)
+	if !valueIsString || !patternIsString {
+		return nil, fmt.Errorf("Operator %v in when expression can only be used with strings", n.Operator)
+	}
+
+	match, err := compiledRegexes.MatchString("^("+pattern+")$", value)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Operator == "!~" {
+		return !match, nil
+	}
+
+	return match, nil
+}
+
+func (n *WhenVariableNode) String() string {
+	if n.Key != "" {
+		return fmt.Sprintf("%v['%v']", n.Name, n.Key)
+	}
+	return n.Name
+}
+
+func (n *WhenVariableNode) evaluate(context WhenContext) (interface{}, error) {
+	switch n.Name {
+	case "status":
+		return context.Status, nil
+	case "branch":
+		return context.Branch, nil
+	case "server":
+		return context.Server, nil
+	case "action":
+		return context.Action, nil
+	case "labels":
+		return context.Labels[n.Key], nil
+	case "env":
+		return context.Env[n.Key], nil
+	}
+
+	return nil, fmt.Errorf("Unknown variable %v in when expression, use status, branch, server, action, labels or env", n.Name)
+}
+
+// isKnownWhenVariable indicates whether the variable is one of the WhenContext
+func isKnownWhenVariable(name string) bool {
+	switch name {
+	case "status", "branch", "server", "action", "labels", "env":
+		return true
+	}
+
+	return false
+}
+
+func (n *WhenLiteralNode) String() string {
+	if s, ok := n.Value.(string); ok {
+		return fmt.Sprintf("'%v'", s)
+	}
+	return fmt.Sprintf("%v", n.Value)
+}
+
+func (n *WhenLiteralNode) evaluate(context WhenContext) (interface{}, error) {
+	return n.Value, nil
+}
+
+func evaluateWhenBool(node WhenNode, context WhenContext) (bool, error) {
+	value, err := node.evaluate(context)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("Expected true or false for %v in when expression", node)
+	}
+
+	return result, nil
+}
+
+type whenTokenKind int
+
+const (
+	whenTokenEnd whenTokenKind = iota
+	whenTokenIdentifier
+	whenTokenString
+	whenTokenOperator
+	whenTokenOpenParenthesis
+	whenTokenCloseParenthesis
+	whenTokenOpenBracket
+	whenTokenCloseBracket
+)
+
+type whenToken struct {
+	kind     whenTokenKind
+	value    string
+	position int
+}
+
+func (t whenToken) String() string {
+	if t.kind == whenTokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%v'", t.value)
+}
+
+func tokenizeWhenExpression(expression string) (tokens []whenToken, err error) {
+
+	for i := 0; i < len(expression); {
+		c := expression[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			kind := map[byte]whenTokenKind{'(': whenTokenOpenParenthesis, ')': whenTokenCloseParenthesis, '[': whenTokenOpenBracket, ']': whenTokenCloseBracket}[c]
+			tokens = append(tokens, whenToken{kind: kind, value: string(c), position: i})
+			i++
+
+		case c == '\'' || c == '"':
+			var value strings.Builder
+			start := i
+			i++
+			for ; i < len(expression) && expression[i] != c; i++ {
+				// only the quote and backslash itself need escaping, so regular expressions can be used as is
+				if expression[i] == '\\' && i+1 < len(expression) && (expression[i+1] == c || expression[i+1] == '\\') {
+					i++
+				}
+				value.WriteByte(expression[i])
+			}
+			if i >= len(expression) {
+				return nil, fmt.Errorf("Unterminated string at position %v in when expression", start)
+			}
+			tokens = append(tokens, whenToken{kind: whenTokenString, value: value.String(), position: start})
+			i++
+
+		case isWhenIdentifierStart(c):
+			start := i
+			for i < len(expression) && (isWhenIdentifierStart(expression[i]) || (expression[i] >= '0' && expression[i] <= '9') || expression[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, whenToken{kind: whenTokenIdentifier, value: expression[start:i], position: start})
+
+		default:
+			operator := ""
+			for _, o := range []string{"==", "!=", "=~", "!~", "&&", "||", "!"} {
+				if strings.HasPrefix(expression[i:], o) {
+					operator = o
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("Unexpected character '%v' at position %v in when expression", string(c), i)
+			}
+			tokens = append(tokens, whenToken{kind: whenTokenOperator, value: operator, position: i})
+			i += len(operator)
+		}
+	}
+
+	tokens = append(tokens, whenToken{kind: whenTokenEnd, position: len(expression)})
+
+	return
+}
+
+func isWhenIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type whenParser struct {
+	tokens   []whenToken
+	position int
+}
+
+func (p *whenParser) peek() whenToken {
+	return p.tokens[p.position]
+}
+
+func (p *whenParser) next() whenToken {
+	t := p.tokens[p.position]
+	if t.kind != whenTokenEnd {
+		p.position++
+	}
+	return t
+}
+
+func (p *whenParser) parseOr() (WhenNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == whenTokenOperator && p.peek().value == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &WhenLogicalNode{Operator: "||", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *whenParser) parseAnd() (WhenNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == whenTokenOperator && p.peek().value == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &WhenLogicalNode{Operator: "&&", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *whenParser) parseNot() (WhenNode, error) {
+	if p.peek().kind == whenTokenOperator && p.peek().value == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &WhenNotNode{Operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *whenParser) parseComparison() (WhenNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == whenTokenOperator && (t.value == "==" || t.value == "!=" || t.value == "=~" || t.value == "!~") {
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &WhenComparisonNode{Operator: t.value, Left: left, Right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *whenParser) parseOperand() (WhenNode, error) {
+	t := p.next()
+
+	switch t.kind {
+	case whenTokenOpenParenthesis:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != whenTokenCloseParenthesis {
+			return nil, fmt.Errorf("Expected ')' at position %v in when expression, got %v", closing.position, closing)
+		}
+		return node, nil
+
+	case whenTokenString:
+		return &WhenLiteralNode{Value: t.value}, nil
+
+	case whenTokenIdentifier:
+		return p.parseIdentifier(t)
+	}
+
+	return nil, fmt.Errorf("Unexpected %v at position %v in when expression", t, t.position)
+}
+
+func (p *whenParser) parseIdentifier(t whenToken) (WhenNode, error) {
+	if t.value == "true" || t.value == "false" {
+		return &WhenLiteralNode{Value: t.value == "true"}, nil
+	}
+
+	name, key := t.value, ""
+	if i := strings.Index(name, "."); i >= 0 {
+		name, key = name[:i], name[i+1:]
+	}
+
+	switch name {
+	case "status", "branch", "server", "action":
+		if key != "" || p.peek().kind == whenTokenOpenBracket {
+			return nil, fmt.Errorf("Variable %v at position %v in when expression has no keys", name, t.position)
+		}
+
+	case "labels", "env":
+		if key == "" {
+			if p.next().kind != whenTokenOpenBracket {
+				return nil, fmt.Errorf("Variable %v at position %v in when expression needs a key, i.e. %v.NAME or %v['NAME']", name, t.position, name, name)
+			}
+			keyToken := p.next()
+			if keyToken.kind != whenTokenString {
+				return nil, fmt.Errorf("Expected quoted key at position %v in when expression, got %v", keyToken.position, keyToken)
+			}
+			if closing := p.next(); closing.kind != whenTokenCloseBracket {
+				return nil, fmt.Errorf("Expected ']' at position %v in when expression, got %v", closing.position, closing)
+			}
+			key = keyToken.value
+		}
+
+	default:
+		// servers can provide more variables than the documented ones, so unknown variables only fail on evaluation and validation
+		// reports them as warning
+		key = ""
+		name = t.value
+	}
+
+	return &WhenVariableNode{Name: name, Key: key}, nil
+}
@@ -0,0 +1,206 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWhenExpression(t *testing.T) {
+	t.Run("ReturnsAbstractSyntaxTreeWithAndBindingStrongerThanOr", func(t *testing.T) {
+
+		// act
+		expression, err := ParseWhenExpression("status == 'failed' || status == 'succeeded' && branch == 'main'")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "((status == 'failed') || ((status == 'succeeded') && (branch == 'main')))", expression.Root.String())
+	})
+
+	t.Run("ReturnsAbstractSyntaxTreeRespectingParentheses", func(t *testing.T) {
+
+		// act
+		expression, err := ParseWhenExpression("(status == 'failed' || status == 'succeeded') && !(branch =~ 'release-.*')")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "(((status == 'failed') || (status == 'succeeded')) && !(branch =~ 'release-.*'))", expression.Root.String())
+	})
+
+	t.Run("ReturnsVariableWithKeyForLabelsAndEnvInDotAndBracketNotation", func(t *testing.T) {
+
+		// act
+		expression, err := ParseWhenExpression(`labels.app == "api" && env['VAR_A'] != ''`)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "((labels['app'] == 'api') && (env['VAR_A'] != ''))", expression.Root.String())
+	})
+
+	t.Run("ReturnsErrorForUnterminatedString", func(t *testing.T) {
+
+		// act
+		_, err := ParseWhenExpression("status == 'succeeded")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForSingleEqualsSign", func(t *testing.T) {
+
+		// act
+		_, err := ParseWhenExpression("status = 'succeeded'")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForUnbalancedParentheses", func(t *testing.T) {
+
+		// act
+		_, err := ParseWhenExpression("(status == 'succeeded' && branch == 'main'")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForMissingOperand", func(t *testing.T) {
+
+		// act
+		_, err := ParseWhenExpression("status == 'succeeded' &&")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForLabelsWithoutKey", func(t *testing.T) {
+
+		// act
+		_, err := ParseWhenExpression("labels == 'api'")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWhenExpressionEvaluate(t *testing.T) {
+
+	context := WhenContext{
+		Status: "succeeded",
+		Branch: "main",
+		Server: "ziplinee",
+		Action: "deploy-canary",
+		Labels: map[string]string{"app": "api"},
+		Env:    map[string]string{"VAR_A": "Greetings"},
+	}
+
+	t.Run("ReturnsTrueIfStatusAndBranchMatch", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("status == 'succeeded' && branch == 'main'")
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("ReturnsFalseIfBranchDoesNotMatch", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("status == 'succeeded' && branch == 'master'")
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("ReturnsTrueIfEitherSideOfOrMatches", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("status == 'failed' || server == 'ziplinee'")
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("ReturnsTrueIfRegexMatchesFullValue", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression(`action =~ 'deploy-\w+' && branch !~ 'release'`)
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("ReturnsFalseIfRegexOnlyMatchesSubstring", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("action =~ 'deploy'")
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("ReturnsNegatedResultForNotOperator", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("!(labels.app == 'api') || env.VAR_A != 'Greetings'")
+		assert.Nil(t, err)
+
+		// act
+		result, err := expression.Evaluate(context)
+
+		assert.Nil(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("ReturnsErrorForUnknownVariable", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("trigger == 'git'")
+		assert.Nil(t, err)
+
+		// act
+		_, err = expression.Evaluate(context)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfExpressionDoesNotEvaluateToABoolean", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("status")
+		assert.Nil(t, err)
+
+		// act
+		_, err = expression.Evaluate(context)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWhenExpressionUnknownVariables(t *testing.T) {
+	t.Run("ReturnsNoVariablesForKnownVariables", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("status == 'succeeded' && (labels.app == 'api' || !(env['VAR_A'] =~ 'Hi.*'))")
+		assert.Nil(t, err)
+
+		// act
+		names := expression.UnknownVariables()
+
+		assert.Equal(t, 0, len(names))
+	})
+
+	t.Run("ReturnsUnknownVariablesOnceInOrderOfUse", func(t *testing.T) {
+
+		expression, err := ParseWhenExpression("stauts == 'succeeded' || !(trigger == 'git' && stauts != 'failed')")
+		assert.Nil(t, err)
+
+		// act
+		names := expression.UnknownVariables()
+
+		assert.Equal(t, []string{"stauts", "trigger"}, names)
+	})
+}